@@ -40,6 +40,8 @@ func createCliApp() *cli.App {
 	cliapp.Commands = []cli.Command{
 		createTestCommand(),
 		createAddCommand(),
+		createLintCommand(),
+		createSchemaCommand(),
 	}
 	return cliapp
 }
@@ -58,9 +60,26 @@ Examples:
 Directory test:
 commander test --dir /your/dir/
 
+Recursive directory test:
+commander test --dir --recursive --include-files "**/*_test.yaml" --exclude-files "_fixtures" /your/dir/
+
+Parallel directory test:
+commander test --dir --parallel 4 /your/dir/
+
 Stdin test:
 cat commander.yaml | commander test -
 
+JSON and TOML suites:
+commander test commander.json
+cat commander.toml | commander test --format toml -
+
+Markdown test:
+commander test README.md
+
+Transcript test:
+commander test cli.t
+commander test --update cli.t
+
 HTTP test:
 commander test https://your-url/commander_test.yaml
 
@@ -72,6 +91,15 @@ commander test commander.yaml --filter=filter1 --filter=filter2
 
 Regex filters:
 commander test commander.yaml --filter="^filter1$"
+
+Excluding tests:
+commander test commander.yaml --exclude="slow$"
+
+File qualified filters in directories:
+commander test --filter="api_test.yaml:^login" --dir /your/dir/
+
+Node filters:
+commander test commander.yaml --node=docker-host
 `,
 		ArgsUsage: "[file] [--filter]",
 		Flags: []cli.Flag{
@@ -87,7 +115,32 @@ commander test commander.yaml --filter="^filter1$"
 			},
 			cli.BoolFlag{
 				Name:  "dir",
-				Usage: "Execute all test files in a directory sorted by file name, this is not recursive - e.g. /path/to/test_files/",
+				Usage: "Execute all test files in a directory sorted by file name, only .yaml, .yml, .json, .toml and .t files are executed by default - e.g. /path/to/test_files/",
+			},
+			cli.BoolFlag{
+				Name:  "recursive",
+				Usage: "Execute the test files of all sub directories with --dir",
+			},
+			cli.StringSliceFlag{
+				Name:  "include-files",
+				Usage: "Execute only test files which match the glob pattern with --dir, ** matches any amount of directories - e.g. **/*_test.yaml",
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude-files",
+				Usage: "Do not execute test files or directories which match the glob pattern with --dir",
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of the test suites, yaml, json, toml, markdown or transcript. By default it is detected by the file extension and yaml is used for stdin",
+			},
+			cli.BoolFlag{
+				Name:  "update",
+				Usage: "Rewrite the expected output and exit codes of transcript files with the output of their commands",
+			},
+			cli.IntFlag{
+				Name:  "parallel",
+				Value: 1,
+				Usage: "Maximum amount of test files which are executed concurrently with --dir, the output is grouped by file, can not be combined with --verbose",
 			},
 			cli.StringFlag{
 				Name:  "workdir",
@@ -99,7 +152,27 @@ commander test commander.yaml --filter="^filter1$"
 			},
 			cli.StringSliceFlag{
 				Name:  "filter",
-				Usage: `Filter tests by a given regex pattern. Tests are filtered by its title.`,
+				Usage: `Filter tests by a given regex pattern. Tests are filtered by its title, the pattern can be qualified with a file - e.g. "api_test.yaml:^login"`,
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: `Do not execute tests whose title matches the regex pattern, the pattern can be qualified with a file like --filter`,
+			},
+			cli.StringSliceFlag{
+				Name:  "node",
+				Usage: `Execute tests only on the given nodes`,
+			},
+			cli.BoolFlag{
+				Name:  "allow-empty",
+				Usage: `Do not fail if a filter or node does not select any test`,
+			},
+			cli.StringSliceFlag{
+				Name:  "tag",
+				Usage: `Execute only tests whose tags match the expression, tags are combined with &&, ||, ! and parentheses - e.g. "smoke && !slow"`,
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude-tag",
+				Usage: `Do not execute tests whose tags match the expression`,
 			},
 		},
 		Action: func(c *cli.Context) error {
@@ -160,3 +233,69 @@ func createAddCommand() cli.Command {
 		},
 	}
 }
+
+func createLintCommand() cli.Command {
+	return cli.Command{
+		Name:  "lint",
+		Usage: "Validate test suites without executing them",
+		UsageText: `Validate test suites without executing them
+
+All problems are printed with their file, line and column, the exit code is 1 if a suite is invalid.
+Suites are validated before they are executed by the test command as well.
+
+Examples:
+
+commander lint commander.yaml
+commander lint --dir --recursive /your/dir/
+cat commander.yaml | commander lint -
+`,
+		ArgsUsage: "[file]",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "dir",
+				Usage: "Validate all test files in a directory, only .yaml, .yml, .json, .toml and .t files are validated by default",
+			},
+			cli.BoolFlag{
+				Name:  "recursive",
+				Usage: "Validate the test files of all sub directories with --dir",
+			},
+			cli.StringSliceFlag{
+				Name:  "include-files",
+				Usage: "Validate only test files which match the glob pattern with --dir",
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude-files",
+				Usage: "Do not validate test files or directories which match the glob pattern with --dir",
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of the test suites, yaml, json, toml, markdown or transcript. By default it is detected by the file extension and yaml is used for stdin",
+			},
+			cli.StringFlag{
+				Name:  "config",
+				Usage: "Default config file which is validated with the test files",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return app.LintCommand(c.Args().First(), app.NewLintContextFromCli(c))
+		},
+	}
+}
+
+func createSchemaCommand() cli.Command {
+	return cli.Command{
+		Name:  "schema",
+		Usage: "Print the JSON Schema of the test suites",
+		UsageText: `Print the JSON Schema of the test suites
+
+Editors can use the schema to complete and validate the suites.
+
+Examples:
+
+commander schema > commander.schema.json
+`,
+		Action: func(c *cli.Context) error {
+			return app.SchemaCommand()
+		},
+	}
+}
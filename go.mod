@@ -3,6 +3,7 @@ module github.com/commander-cli/commander/v2
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/antchfx/xmlquery v1.3.18
 	github.com/commander-cli/cmd v1.6.0
 	github.com/docker/docker v24.0.7+incompatible
@@ -19,6 +20,7 @@ require (
 	golang.org/x/time v0.0.0-20220210224613-90d013bbcef8 // indirect
 	gopkg.in/h2non/gock.v1 v1.0.16
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -43,6 +45,5 @@ require (
 	golang.org/x/sys v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 	golang.org/x/tools v0.14.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gotest.tools/v3 v3.0.2 // indirect
 )
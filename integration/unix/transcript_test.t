@@ -0,0 +1,21 @@
+Transcripts are executed like a shell session:
+
+  $ echo hello
+  hello
+  $ printf 'one\ntwo\n'
+  one
+  two
+
+Patterns match lines with changing output:
+
+  $ date +%Y-%m-%d
+  \d{4}-\d{2}-\d{2} (re)
+  $ echo "build $$"
+  build * (glob)
+
+Exit codes are annotated after the output:
+
+  $ echo failed &&
+  > exit 2
+  failed
+  [2]
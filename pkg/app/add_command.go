@@ -32,11 +32,18 @@ func AddCommand(command string, existed []byte) ([]byte, error) {
 
 		for k, t := range conf.Tests {
 			test := suite.YAMLTest{
-				Title:    t.Title,
-				Stdout:   t.Stdout.(runtime.ExpectedOut),
-				Stderr:   t.Stderr.(runtime.ExpectedOut),
-				ExitCode: t.ExitCode,
-				Config:   convertConfig(t.Config),
+				Title:       t.Title,
+				Description: t.Description,
+				Stdout:      t.Stdout.(runtime.ExpectedOut),
+				Stderr:      t.Stderr.(runtime.ExpectedOut),
+				Output:      t.Output,
+				ExitCode:    t.ExitCode,
+				Config:      convertConfig(t.Config),
+				Validate:    t.Validate,
+				RawOutput:   t.RawOutput,
+				Tags:        t.Tags,
+				RunIf:       t.RunIf,
+				SkipIf:      t.SkipIf,
 			}
 
 			//If title and command are not equal add the command property to the struct
@@ -66,7 +73,7 @@ func AddCommand(command string, existed []byte) ([]byte, error) {
 }
 
 func convertConfig(config suite.YAMLTestConfigConf) suite.YAMLTestConfigConf {
-	if config.Dir == "" && len(config.Env) == 0 && config.Timeout == "" {
+	if config.Dir == "" && len(config.Env) == 0 && config.Timeout == "" && config.MaxOutput == "" {
 		return suite.YAMLTestConfigConf{}
 	}
 	return config
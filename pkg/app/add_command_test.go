@@ -71,3 +71,77 @@ tests:
 	assert.Nil(t, err)
 	assert.Equal(t, string(expected), string(content))
 }
+
+func Test_AddCommand_KeepsPropertiesOfExistingTests(t *testing.T) {
+	existing := []byte(`tests:
+  exists:
+    description: guards the exit code
+    command: echo exists
+    exit-code: 0
+    tags: [smoke, fast]
+    run-if:
+      os: [linux, darwin]
+      binary: docker
+    skip-if:
+      env: CI=true
+`)
+
+	content, err := AddCommand("echo hello", existing)
+
+	expected := []byte(`tests:
+  echo hello:
+    exit-code: 0
+    stdout: hello
+  exists:
+    description: guards the exit code
+    command: echo exists
+    exit-code: 0
+    tags:
+    - smoke
+    - fast
+    run-if:
+      os:
+      - linux
+      - darwin
+      binary: docker
+    skip-if:
+      env: CI=true
+`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(content))
+}
+
+func Test_AddCommand_KeepsCountedContains(t *testing.T) {
+	existing := []byte(`tests:
+  exists:
+    command: echo hello
+    exit-code: 0
+    stdout:
+      contains:
+      - hello
+      - text: l
+        count: 2
+      line-count: 1
+`)
+
+	content, err := AddCommand("echo hello", existing)
+
+	expected := []byte(`tests:
+  echo hello:
+    exit-code: 0
+    stdout: hello
+  exists:
+    command: echo hello
+    exit-code: 0
+    stdout:
+      contains:
+      - hello
+      - text: l
+        count: 2
+      line-count: 1
+`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(content))
+}
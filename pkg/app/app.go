@@ -20,6 +20,24 @@ type TestCommandContext struct {
 	Concurrent int
 	Config     string
 	Filters    []string
+	// Recursive, IncludeFiles and ExcludeFiles select the suites which are executed with Dir
+	Recursive    bool
+	IncludeFiles []string
+	ExcludeFiles []string
+	// Format is the format of the suites, by default it is detected by the file extension
+	Format string
+	// Update rewrites the expected output of transcripts with the output of their tests
+	Update bool
+	// Parallel is the maximum amount of suites which are executed concurrently with Dir
+	Parallel int
+	// Tags and ExcludeTags are tag expressions which select the executed tests, see runtime.TagFilter
+	Tags        []string
+	ExcludeTags []string
+	// Excludes are title filters of tests which are not executed, Nodes restrict the nodes tests are executed on
+	Excludes []string
+	Nodes    []string
+	// AllowEmpty does not fail if a filter or node does not select any test
+	AllowEmpty bool
 }
 
 // NewTestContextFromCli is a constructor which creates the context
@@ -32,5 +50,39 @@ func NewTestContextFromCli(c *cli.Context) TestCommandContext {
 		Concurrent: c.Int("concurrent"),
 		Config:     c.String("config"),
 		Filters:    c.StringSlice("filter"),
+
+		Recursive:    c.Bool("recursive"),
+		IncludeFiles: c.StringSlice("include-files"),
+		ExcludeFiles: c.StringSlice("exclude-files"),
+		Parallel:     c.Int("parallel"),
+		Format:       c.String("format"),
+		Update:       c.Bool("update"),
+		Tags:         c.StringSlice("tag"),
+		ExcludeTags:  c.StringSlice("exclude-tag"),
+		Excludes:     c.StringSlice("exclude"),
+		Nodes:        c.StringSlice("node"),
+		AllowEmpty:   c.Bool("allow-empty"),
+	}
+}
+
+// LintCommandContext holds all flags for the lint command
+type LintCommandContext struct {
+	Dir          bool
+	Recursive    bool
+	IncludeFiles []string
+	ExcludeFiles []string
+	Format       string
+	Config       string
+}
+
+// NewLintContextFromCli is a constructor which creates the context
+func NewLintContextFromCli(c *cli.Context) LintCommandContext {
+	return LintCommandContext{
+		Dir:          c.Bool("dir"),
+		Recursive:    c.Bool("recursive"),
+		IncludeFiles: c.StringSlice("include-files"),
+		ExcludeFiles: c.StringSlice("exclude-files"),
+		Format:       c.String("format"),
+		Config:       c.String("config"),
 	}
 }
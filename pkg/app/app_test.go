@@ -12,6 +12,7 @@ func TestNewTestCommandContextFromCli(t *testing.T) {
 	set.Bool("verbose", true, "")
 	set.Bool("no-color", true, "")
 	set.Int("concurrent", 5, "")
+	set.Int("parallel", 4, "")
 
 	context := &cli.Context{}
 	ctx := cli.NewContext(nil, set, context)
@@ -21,4 +22,5 @@ func TestNewTestCommandContextFromCli(t *testing.T) {
 	assert.True(t, r.Verbose)
 	assert.True(t, r.NoColor)
 	assert.Equal(t, 5, r.Concurrent)
+	assert.Equal(t, 4, r.Parallel)
 }
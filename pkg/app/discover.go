@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// suiteExtensions are the file extensions of suites which are executed in directory mode
+var suiteExtensions = []string{".yaml", ".yml", ".json", ".toml", ".t"}
+
+// discoverOptions configure which files of a directory are executed
+type discoverOptions struct {
+	// Recursive executes the suites of all sub directories
+	Recursive bool
+	// Include holds glob patterns of the files which are executed, by default all files with a suite extension are executed
+	Include []string
+	// Exclude holds glob patterns of files and directories which are not executed
+	Exclude []string
+}
+
+// findSuites returns the paths of the suites in the directory relative to the directory, sorted by path.
+// Patterns without a slash match the file name, patterns with a slash match the relative path and ** matches any amount of directories.
+func findSuites(directory string, opts discoverOptions) ([]string, error) {
+	for _, p := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("Error: invalid pattern '%s': %s", p, err)
+		}
+	}
+
+	var files []string
+	err := filepath.WalkDir(directory, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(directory, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel == "." {
+				return nil
+			}
+			if !opts.Recursive || matchAny(opts.Exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if isSuite(rel, opts) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+func isSuite(file string, opts discoverOptions) bool {
+	if matchAny(opts.Exclude, file) {
+		return false
+	}
+
+	if len(opts.Include) > 0 {
+		return matchAny(opts.Include, file)
+	}
+
+	ext := strings.ToLower(path.Ext(file))
+	for _, e := range suiteExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash separated path against the pattern
+func matchGlob(pattern string, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern []string, file []string) bool {
+	if len(pattern) == 0 {
+		return len(file) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(file); i++ {
+			if matchSegments(pattern[1:], file[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(file) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], file[0])
+	return ok && matchSegments(pattern[1:], file[1:])
+}
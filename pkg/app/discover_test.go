@@ -0,0 +1,85 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createSuiteDir(t *testing.T, files ...string) string {
+	dir := t.TempDir()
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.Nil(t, os.WriteFile(p, []byte("tests: {}"), 0o644))
+	}
+	return dir
+}
+
+func Test_FindSuites_SkipsSubDirectoriesAndOtherExtensions(t *testing.T) {
+	dir := createSuiteDir(t, "a.yaml", "b.yml", "README.md", "sub/c.yaml")
+
+	files, err := findSuites(dir, discoverOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.yaml", "b.yml"}, files)
+}
+
+func Test_FindSuites_Recursive(t *testing.T) {
+	dir := createSuiteDir(t, "b.yaml", "a/c.yaml", "a/b/d.yaml", "c.txt")
+
+	files, err := findSuites(dir, discoverOptions{Recursive: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a/b/d.yaml", "a/c.yaml", "b.yaml"}, files)
+}
+
+func Test_FindSuites_IncludeAndExclude(t *testing.T) {
+	dir := createSuiteDir(t,
+		"main_test.yaml",
+		"config.yaml",
+		"api/users_test.yaml",
+		"api/_fixtures/fixture_test.yaml",
+		"cli/slow_test.yaml",
+	)
+
+	files, err := findSuites(dir, discoverOptions{
+		Recursive: true,
+		Include:   []string{"**/*_test.yaml"},
+		Exclude:   []string{"_fixtures", "cli/slow_*"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"api/users_test.yaml", "main_test.yaml"}, files)
+}
+
+func Test_FindSuites_InvalidPattern(t *testing.T) {
+	_, err := findSuites(t.TempDir(), discoverOptions{Include: []string{"[a"}})
+
+	assert.Equal(t, "Error: invalid pattern '[a': syntax error in pattern", err.Error())
+}
+
+func Test_MatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		match   bool
+	}{
+		{"*.yaml", "a.yaml", true},
+		{"*.yaml", "sub/a.yaml", true},
+		{"*.yaml", "a.yml", false},
+		{"sub/*.yaml", "sub/a.yaml", true},
+		{"sub/*.yaml", "sub/dir/a.yaml", false},
+		{"sub/**/*.yaml", "sub/a.yaml", true},
+		{"sub/**/*.yaml", "sub/dir/deep/a.yaml", true},
+		{"**/*_test.yaml", "a_test.yaml", true},
+		{"**/*_test.yaml", "dir/a.yaml", false},
+		{"**", "dir/a.yaml", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, matchGlob(test.pattern, test.file), "%s %s", test.pattern, test.file)
+	}
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/commander-cli/commander/v2/pkg/suite"
+)
+
+// LintCommand validates the suites without executing them and prints all problems with their positions.
+// lintPath is the path to the suite, it can be a dir with --dir or - for stdin
+func LintCommand(lintPath string, ctx LintCommandContext) error {
+	suiteFormat = ""
+	if ctx.Format != "" {
+		format, err := suite.ParseFormat(ctx.Format)
+		if err != nil {
+			return fmt.Errorf("Error: " + err.Error())
+		}
+		suiteFormat = format
+	}
+
+	if lintPath == "" {
+		lintPath = CommanderFile
+	}
+
+	files := []string{lintPath}
+	if ctx.Dir {
+		suites, err := findSuites(lintPath, discoverOptions{Recursive: ctx.Recursive, Include: ctx.IncludeFiles, Exclude: ctx.ExcludeFiles})
+		if err != nil {
+			return err
+		}
+		files = nil
+		for _, s := range suites {
+			files = append(files, path.Join(lintPath, s))
+		}
+	}
+
+	var config []byte
+	if ctx.Config != "" {
+		var err error
+		if config, err = readFile(ctx.Config); err != nil {
+			return fmt.Errorf("Error " + err.Error())
+		}
+	}
+
+	problems := 0
+	invalid := 0
+	// the --config file and included suites are linted with every suite, their problems are printed once
+	printed := make(map[string]bool)
+	for _, f := range files {
+		errs, err := lintFile(f, ctx.Config, config)
+		if err != nil {
+			return fmt.Errorf("Error " + err.Error())
+		}
+
+		if len(errs) > 0 {
+			invalid++
+		}
+		for _, e := range errs {
+			if printed[e.Error()] {
+				continue
+			}
+			printed[e.Error()] = true
+			problems++
+			fmt.Println(e.Error())
+		}
+	}
+
+	if problems > 0 {
+		fmt.Println("")
+		return fmt.Errorf("Found %d problems in %d of %d files", problems, invalid, len(files))
+	}
+
+	fmt.Printf("No problems found in %d files\n", len(files))
+	return nil
+}
+
+// lintFile lints the suite with the --config file, the suite is read from stdin if the path is -
+func lintFile(filePath string, configPath string, config []byte) (suite.ValidationErrors, error) {
+	l := suite.NewLinter()
+
+	if filePath == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		format := suiteFormat
+		if format == "" {
+			format = suite.FormatYAML
+		}
+		l.Lint(content, format, "", "stdin")
+	} else {
+		content, err := readFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		l.Lint(content, formatOf(filePath), filePath, "")
+	}
+
+	if configPath != "" {
+		l.Lint(config, suite.FormatYAML, configPath, "")
+	}
+	return l.Errors(), nil
+}
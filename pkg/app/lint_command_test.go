@@ -0,0 +1,42 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LintCommand(t *testing.T) {
+	var err error
+	out := captureOutput(func() {
+		err = LintCommand("testdata/test.yaml", LintCommandContext{})
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "No problems found in 1 files")
+}
+
+func Test_LintCommand_Dir(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(dir+"/a.yaml", []byte("tests:\n  echo a:\n    stdout:\n      contain: a\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/b.yaml", []byte("tests:\n  echo b:\n    exit-code: 0\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/config.yaml", []byte("config:\n  nodes: [missing]\n"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = LintCommand(dir, LintCommandContext{Dir: true, ExcludeFiles: []string{"config.yaml"}, Config: dir + "/config.yaml"})
+	})
+
+	assert.Equal(t, "Found 2 problems in 2 of 2 files", err.Error())
+	assert.Contains(t, out, dir+"/a.yaml:4:7: unknown assertion 'contain'\n")
+	assert.Contains(t, out, dir+"/config.yaml:2:11: unknown node 'missing', expected one of: local\n")
+}
+
+func Test_LintCommand_Errors(t *testing.T) {
+	err := LintCommand("missing.yaml", LintCommandContext{})
+	assert.Equal(t, "Error open missing.yaml: no such file or directory", err.Error())
+
+	err = LintCommand("testdata/test.yaml", LintCommandContext{Format: "xml"})
+	assert.Equal(t, "Error: unknown format 'xml', expected yaml, json, toml, markdown or transcript", err.Error())
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/commander-cli/commander/v2/pkg/suite"
+)
+
+// SchemaCommand prints the JSON Schema of the suites
+func SchemaCommand() error {
+	s, err := suite.Schema()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(s))
+	return nil
+}
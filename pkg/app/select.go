@@ -0,0 +1,207 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+)
+
+// qualifierExtensions are the extensions of the suites which can qualify a filter, i.e. file.yaml:title
+var qualifierExtensions = append([]string{".md", ".markdown"}, suiteExtensions...)
+
+// testSelection selects the executed tests with --filter, --exclude, --node, --tag and --exclude-tag.
+// Filters and nodes which do not select any test of all executed suites are reported by unmatched.
+type testSelection struct {
+	filters  []titleFilter
+	excludes []titleFilter
+	nodes    []string
+	tags     runtime.TagFilter
+
+	mu             sync.Mutex
+	matchedFilters map[string]bool
+	matchedNodes   map[string]bool
+}
+
+// titleFilter matches the title of a test with a regex, if file is set only tests of the matching suites are matched
+type titleFilter struct {
+	source string
+	// file is a glob of the suite path, see matchGlob
+	file  string
+	title *regexp.Regexp
+}
+
+// newTestSelection parses the filters of the context, all invalid filters are returned in one error
+func newTestSelection(ctx TestCommandContext) (*testSelection, error) {
+	s := &testSelection{
+		nodes:          ctx.Nodes,
+		matchedFilters: make(map[string]bool),
+		matchedNodes:   make(map[string]bool),
+	}
+
+	var invalid []string
+	parse := func(filters []string, kind string) []titleFilter {
+		var parsed []titleFilter
+		for _, f := range filters {
+			tf, err := parseTitleFilter(f)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("invalid %s '%s': %s", kind, f, err))
+				continue
+			}
+			parsed = append(parsed, tf)
+		}
+		return parsed
+	}
+	s.filters = parse(ctx.Filters, "filter")
+	s.excludes = parse(ctx.Excludes, "exclude")
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("Error: %s", strings.Join(invalid, "\n"))
+	}
+
+	tags, err := runtime.NewTagFilter(ctx.Tags, ctx.ExcludeTags)
+	if err != nil {
+		return nil, fmt.Errorf("Error: " + err.Error())
+	}
+	s.tags = tags
+
+	return s, nil
+}
+
+// parseTitleFilter parses a title regex which can be qualified with the suite, i.e. file.yaml:title.
+// The part before the first colon is only used as the suite if it has a suite extension.
+func parseTitleFilter(filter string) (titleFilter, error) {
+	f := titleFilter{source: filter}
+	pattern := filter
+	if i := strings.Index(filter, ":"); i > 0 && hasQualifierExtension(filter[:i]) {
+		f.file = filter[:i]
+		pattern = filter[i+1:]
+		if _, err := path.Match(f.file, ""); err != nil {
+			return f, err
+		}
+	}
+
+	title, err := regexp.Compile(pattern)
+	if err != nil {
+		return f, err
+	}
+	f.title = title
+	return f, nil
+}
+
+func hasQualifierExtension(file string) bool {
+	ext := strings.ToLower(path.Ext(file))
+	for _, e := range qualifierExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (f titleFilter) match(file string, t runtime.TestCase) bool {
+	if f.file != "" && !matchGlob(f.file, file) {
+		return false
+	}
+	return f.title.MatchString(t.Title)
+}
+
+// selectTests returns the tests of the suite file which are selected, the nodes of the tests are restricted to --node.
+// file is the path of the suite relative to the directory with --dir, otherwise the path which was passed to test.
+func (s *testSelection) selectTests(file string, tests []runtime.TestCase) []runtime.TestCase {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+
+	var selected []runtime.TestCase
+	for _, t := range tests {
+		if len(s.filters) > 0 && !s.matchFilters(file, t) {
+			continue
+		}
+		if s.excluded(file, t) || !s.tags.Match(t.Tags) {
+			continue
+		}
+
+		if len(s.nodes) > 0 {
+			t.Nodes = s.selectNodes(t.Nodes)
+			if len(t.Nodes) == 0 {
+				continue
+			}
+		}
+		selected = append(selected, t)
+	}
+
+	return selected
+}
+
+// matchFilters checks if any filter matches the test, all matching filters are marked as matched
+func (s *testSelection) matchFilters(file string, t runtime.TestCase) bool {
+	matched := false
+	for _, f := range s.filters {
+		if f.match(file, t) {
+			s.matchedFilters[f.source] = true
+			matched = true
+		}
+	}
+	return matched
+}
+
+func (s *testSelection) excluded(file string, t runtime.TestCase) bool {
+	for _, f := range s.excludes {
+		if f.match(file, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectNodes returns the nodes of the test which were selected with --node, tests without nodes are executed locally
+func (s *testSelection) selectNodes(nodes []string) []string {
+	if len(nodes) == 0 {
+		nodes = []string{"local"}
+	}
+
+	var selected []string
+	for _, n := range nodes {
+		if indexOf(s.nodes, n) >= 0 {
+			s.matchedNodes[n] = true
+			selected = append(selected, n)
+		}
+	}
+	return selected
+}
+
+// unmatched returns an error for the filters and nodes which did not select any test
+func (s *testSelection) unmatched() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var problems []string
+	for _, f := range s.filters {
+		if !s.matchedFilters[f.source] {
+			problems = append(problems, fmt.Sprintf("could not find test with pattern: %s", f.source))
+		}
+	}
+	for _, n := range s.nodes {
+		if !s.matchedNodes[n] {
+			problems = append(problems, fmt.Sprintf("could not find test on node: %s", n))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s\nUse --allow-empty if filters may not match any test", strings.Join(problems, "\n"))
+}
+
+func indexOf(values []string, s string) int {
+	for i, v := range values {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
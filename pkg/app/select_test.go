@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func titles(tests []runtime.TestCase) []string {
+	var r []string
+	for _, t := range tests {
+		r = append(r, t.Title)
+	}
+	return r
+}
+
+func Test_ParseTitleFilter(t *testing.T) {
+	f, err := parseTitleFilter("api_test.yaml:^login")
+	assert.Nil(t, err)
+	assert.Equal(t, "api_test.yaml", f.file)
+	assert.Equal(t, "^login", f.title.String())
+
+	// the prefix is only a file if it has a suite extension
+	f, err = parseTitleFilter("echo a:b")
+	assert.Nil(t, err)
+	assert.Equal(t, "", f.file)
+	assert.Equal(t, "echo a:b", f.title.String())
+
+	_, err = parseTitleFilter("[.yaml:title")
+	assert.EqualError(t, err, "syntax error in pattern")
+}
+
+func Test_TestSelection_SelectTests(t *testing.T) {
+	tests := []runtime.TestCase{
+		{Title: "login"},
+		{Title: "login slow", Tags: []string{"slow"}},
+		{Title: "logout"},
+	}
+
+	s, err := newTestSelection(TestCommandContext{
+		Filters:  []string{"api_test.yaml:^log", "other.yaml:logout"},
+		Excludes: []string{"slow$"},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"login", "logout"}, titles(s.selectTests("dir/api_test.yaml", tests)))
+	assert.Nil(t, s.selectTests("sub/ui_test.yaml", tests))
+	assert.EqualError(t, s.unmatched(), "could not find test with pattern: other.yaml:logout\n"+
+		"Use --allow-empty if filters may not match any test")
+}
+
+func Test_TestSelection_Nodes(t *testing.T) {
+	tests := []runtime.TestCase{
+		{Title: "local"},
+		{Title: "docker", Nodes: []string{"docker-host", "ssh-host"}},
+	}
+
+	s, err := newTestSelection(TestCommandContext{Nodes: []string{"ssh-host", "missing"}})
+	assert.Nil(t, err)
+
+	selected := s.selectTests("suite.yaml", tests)
+	assert.Equal(t, []string{"docker"}, titles(selected))
+	assert.Equal(t, []string{"ssh-host"}, selected[0].Nodes)
+	assert.EqualError(t, s.unmatched(), "could not find test on node: missing\n"+
+		"Use --allow-empty if filters may not match any test")
+}
+
+func Test_NewTestSelection_InvalidFilters(t *testing.T) {
+	_, err := newTestSelection(TestCommandContext{Filters: []string{"("}, Excludes: []string{"a.yaml:["}})
+
+	assert.EqualError(t, err, "Error: invalid filter '(': error parsing regexp: missing closing ): `(`\n"+
+		"invalid exclude 'a.yaml:[': error parsing regexp: missing closing ]: `[`")
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/commander-cli/commander/v2/pkg/output"
 	"github.com/commander-cli/commander/v2/pkg/runtime"
@@ -18,12 +20,18 @@ import (
 var (
 	out                 output.OutputWriter
 	overwriteConfigPath string
+	// suiteFormat is the format of all suites, if it is empty the format is detected by the file extension
+	suiteFormat suite.Format
+	// updateTranscripts rewrites the expected output of transcripts with the output of their tests
+	updateTranscripts bool
+	// selection selects the executed tests of all suites
+	selection *testSelection
 )
 
 // TestCommand executes the test argument
 // testPath is the path to the test suite config, it can be a dir or file
-// ctx holds the command flags. If directory scanning is enabled with --dir,
-// test filtering is not supported
+// ctx holds the command flags. Filters are applied to all suites of --dir,
+// filters qualified with a file like file.yaml:title only select tests of the matching suites
 func TestCommand(testPath string, ctx TestCommandContext) error {
 	if ctx.Workdir != "" {
 		err := os.Chdir(ctx.Workdir)
@@ -32,83 +40,226 @@ func TestCommand(testPath string, ctx TestCommandContext) error {
 		}
 	}
 
+	// the log of the executors is global, it can not be grouped with the output of the files
+	if ctx.Verbose && ctx.Dir && ctx.Parallel > 1 {
+		return fmt.Errorf("Error: --verbose can not be combined with --parallel greater than 1")
+	}
+
 	if ctx.Verbose {
 		log.SetOutput(os.Stdout)
 	}
 
 	overwriteConfigPath = ctx.Config
+	suiteFormat = ""
+	if ctx.Format != "" {
+		format, err := suite.ParseFormat(ctx.Format)
+		if err != nil {
+			return fmt.Errorf("Error: " + err.Error())
+		}
+		suiteFormat = format
+	}
+	updateTranscripts = ctx.Update
 	out = output.NewCliOutput(!ctx.NoColor)
 
+	var err error
+	if selection, err = newTestSelection(ctx); err != nil {
+		return err
+	}
+
 	if testPath == "" {
 		testPath = CommanderFile
 	}
 
-	var result runtime.Result
-	var err error
-	switch {
-	case ctx.Dir:
+	if ctx.Dir {
 		fmt.Println("Starting test against directory: " + testPath + "...")
 		fmt.Println("")
-		result, err = testDir(testPath, ctx.Filters)
+		if ctx.Parallel < 0 {
+			return fmt.Errorf("Error: --parallel must not be negative, got %d", ctx.Parallel)
+		}
+
+		result, files, err := testDir(testPath, newDiscoverOptions(ctx), ctx.Parallel)
+		if err != nil {
+			return fmt.Errorf(err.Error())
+		}
+
+		success := out.PrintDirSummary(result, files)
+		if err := checkSelection(ctx); err != nil {
+			return err
+		}
+		if !success && !ctx.Verbose {
+			return fmt.Errorf("Test suite failed, use --verbose for more detailed output")
+		}
+		return nil
+	}
+
+	var result runtime.Result
+	switch {
+	case ctx.Update && (testPath == "-" || isURL(testPath)):
+		return fmt.Errorf("Error: --update is only supported for transcript files")
+	case ctx.Update && formatOf(testPath) != suite.FormatTranscript:
+		return fmt.Errorf("Error: --update is only supported for transcript files, %s is a %s suite", testPath, formatOf(testPath))
 	case testPath == "-":
 		fmt.Println("Starting test from stdin...")
 		fmt.Println("")
-		result, err = testStdin(ctx.Filters)
+		result, err = testStdin()
 	case isURL(testPath):
 		fmt.Println("Starting test from " + testPath + "...")
 		fmt.Println("")
-		result, err = testURL(testPath, ctx.Filters)
+		result, err = testURL(testPath)
 	default:
 		fmt.Println("Starting test file " + testPath + "...")
 		fmt.Println("")
-		result, err = testFile(testPath, "", ctx.Filters)
+		result, err = testFile(&out, testPath, "")
 	}
 
 	if err != nil {
 		return fmt.Errorf(err.Error())
 	}
 
-	if !out.PrintSummary(result) && !ctx.Verbose {
+	success := out.PrintSummary(result)
+	if err := checkSelection(ctx); err != nil {
+		return err
+	}
+	if !success && !ctx.Verbose {
 		return fmt.Errorf("Test suite failed, use --verbose for more detailed output")
 	}
 
 	return nil
 }
 
-func testFile(filePath string, fileName string, filters runtime.Filters) (runtime.Result, error) {
+// checkSelection fails if a filter or node did not select any test of the executed suites, unless --allow-empty is set
+func checkSelection(ctx TestCommandContext) error {
+	if ctx.AllowEmpty {
+		return nil
+	}
+	return selection.unmatched()
+}
+
+func testFile(w *output.OutputWriter, filePath string, fileName string) (runtime.Result, error) {
 	s, err := getSuite(filePath, fileName)
 	if err != nil {
 		return runtime.Result{}, fmt.Errorf("Error " + err.Error())
 	}
 
-	return execute(s, filters)
+	// filters are qualified with the path relative to the directory with --dir
+	qualifier := fileName
+	if qualifier == "" {
+		qualifier = filePath
+	}
+
+	result, err := execute(w, s, qualifier)
+	if err != nil || !updateTranscripts || formatOf(filePath) != suite.FormatTranscript {
+		return result, err
+	}
+
+	return result, updateTranscript(w, filePath, result)
 }
 
-func testDir(directory string, filters runtime.Filters) (runtime.Result, error) {
+// updateTranscript rewrites the transcript with the results of its tests if the output changed
+func updateTranscript(w *output.OutputWriter, filePath string, result runtime.Result) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	updated := suite.UpdateTranscript(content, result.TestResults)
+	if bytes.Equal(content, updated) {
+		return nil
+	}
+
+	f, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, updated, f.Mode()); err != nil {
+		return err
+	}
+
+	w.PrintUpdated(filePath)
+	return nil
+}
+
+// testDir executes all suites of the directory, suites which can not be loaded are reported
+// in the file results and do not stop the execution of the other suites.
+// With parallel > 1 the suites are executed concurrently, the output of each suite is buffered and printed in the order of the files.
+func testDir(directory string, opts discoverOptions, parallel int) (runtime.Result, []output.FileResult, error) {
 	result := runtime.Result{}
-	files, err := os.ReadDir(directory)
+	if f, err := os.Stat(directory); err != nil || !f.IsDir() {
+		return result, nil, fmt.Errorf("Error: Input is not a directory")
+	}
+
+	suites, err := findSuites(directory, opts)
 	if err != nil {
-		return result, fmt.Errorf("Error: Input is not a directory")
+		return result, nil, err
 	}
 
-	for _, f := range files {
-		if f.IsDir() {
-			continue // skip dirs
+	files := make([]output.FileResult, len(suites))
+	if parallel <= 1 {
+		for i, name := range suites {
+			files[i] = testDirFile(&out, directory, name)
+			result = convergeResults(result, files[i].Result)
 		}
+		return result, files, nil
+	}
 
-		p := path.Join(directory, f.Name())
-		newResult, err := testFile(p, f.Name(), filters)
-		if err != nil {
-			return result, err
+	start := time.Now()
+	buffers := make([]bytes.Buffer, len(suites))
+	done := make([]chan struct{}, len(suites))
+	jobs := make(chan int)
+	for i := range suites {
+		done[i] = make(chan struct{})
+	}
+
+	for n := 0; n < parallel; n++ {
+		go func() {
+			for i := range jobs {
+				w := out.WithWriter(&buffers[i])
+				files[i] = testDirFile(&w, directory, suites[i])
+				close(done[i])
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range suites {
+			jobs <- i
 		}
+	}()
 
-		result = convergeResults(result, newResult)
+	for i := range suites {
+		<-done[i]
+		out.Flush(&buffers[i])
+		result = convergeResults(result, files[i].Result)
 	}
+	// the suites were executed concurrently, the duration of the directory is shorter than the sum of its suites
+	result.Duration = time.Since(start)
 
-	return result, nil
+	return result, files, nil
+}
+
+// testDirFile executes a suite of a directory, the panic of an invalid suite is returned as the error of the file
+func testDirFile(w *output.OutputWriter, directory string, fileName string) (file output.FileResult) {
+	file.FileName = fileName
+	defer func() {
+		if r := recover(); r != nil {
+			file.Error = fmt.Errorf("%v", r)
+		}
+	}()
+
+	file.Result, file.Error = testFile(w, path.Join(directory, fileName), fileName)
+	return file
 }
 
-func testURL(url string, filters runtime.Filters) (runtime.Result, error) {
+func newDiscoverOptions(ctx TestCommandContext) discoverOptions {
+	return discoverOptions{
+		Recursive: ctx.Recursive,
+		Include:   ctx.IncludeFiles,
+		Exclude:   ctx.ExcludeFiles,
+	}
+}
+
+func testURL(url string) (runtime.Result, error) {
 	resp, err := http.Get(url)
 	if err != nil {
 		return runtime.Result{}, err
@@ -120,9 +271,18 @@ func testURL(url string, filters runtime.Filters) (runtime.Result, error) {
 		return runtime.Result{}, err
 	}
 
-	s := suite.ParseYAML(body, "")
+	format := suiteFormat
+	if format == "" {
+		format = suite.FormatFromPath(resp.Request.URL.Path)
+	}
+	l := suite.NewLinter()
+	l.Lint(body, format, "", url)
+	if err := validate(l); err != nil {
+		return runtime.Result{}, err
+	}
+	s := suite.Parse(body, format, "")
 
-	return execute(s, filters)
+	return execute(&out, s, url)
 }
 
 func isURL(s string) bool {
@@ -132,12 +292,13 @@ func isURL(s string) bool {
 func convergeResults(result runtime.Result, new runtime.Result) runtime.Result {
 	result.TestResults = append(result.TestResults, new.TestResults...)
 	result.Failed += new.Failed
+	result.Skipped += new.Skipped
 	result.Duration += new.Duration
 
 	return result
 }
 
-func testStdin(filters runtime.Filters) (runtime.Result, error) {
+func testStdin() (runtime.Result, error) {
 	f, err := os.Stdin.Stat()
 	if err != nil {
 		return runtime.Result{}, err
@@ -149,27 +310,26 @@ func testStdin(filters runtime.Filters) (runtime.Result, error) {
 
 	r := bufio.NewReader(os.Stdin)
 	content, err := io.ReadAll(r)
-	s := suite.ParseYAML(content, "")
+	format := suiteFormat
+	if format == "" {
+		format = suite.FormatYAML
+	}
+	l := suite.NewLinter()
+	l.Lint(content, format, "", "stdin")
+	if err := validate(l); err != nil {
+		return runtime.Result{}, err
+	}
+	s := suite.Parse(content, format, "")
 
-	return execute(s, filters)
+	return execute(&out, s, "-")
 }
 
-func execute(s suite.Suite, filters runtime.Filters) (runtime.Result, error) {
-	tests := s.GetTests()
-	if len(filters) != 0 {
-		tests = []runtime.TestCase{}
-	}
-
-	// Filter tests if test filters was given
-	for _, f := range filters {
-		t, err := s.FindTests(f)
-		if err != nil {
-			return runtime.Result{}, err
-		}
-		tests = append(tests, t...)
-	}
+// execute runs the selected tests of the suite, the results are printed by w.
+// file qualifies the filters, see testSelection
+func execute(w *output.OutputWriter, s suite.Suite, file string) (runtime.Result, error) {
+	tests := selection.selectTests(file, s.GetTests())
 
-	r := runtime.NewRuntime(out.GetEventHandler(), s.Nodes...)
+	r := runtime.NewRuntime(w.GetEventHandler(), s.Nodes...)
 	result := r.Start(tests)
 
 	return result, nil
@@ -183,18 +343,43 @@ func getSuite(filePath string, fileName string) (suite.Suite, error) {
 		return suite.Suite{}, err
 	}
 
+	l := suite.NewLinter()
+	l.Lint(content, formatOf(filePath), filePath, fileName)
+
 	overwriteContent := []byte("")
 	if overwriteConfigPath != "" {
 		overwriteContent, err = readFile(overwriteConfigPath)
 		if err != nil {
 			return suite.Suite{}, err
 		}
+		l.Lint(overwriteContent, suite.FormatYAML, overwriteConfigPath, "")
 	}
 
-	s = suite.NewSuite(content, overwriteContent, fileName)
+	// invalid suites are reported with the positions of all problems instead of the panic of the parser
+	if err := validate(l); err != nil {
+		return suite.Suite{}, err
+	}
+
+	s = suite.NewSuiteFromFileWithFormat(filePath, formatOf(filePath), content, overwriteContent, fileName)
 	return s, nil
 }
 
+// validate returns the problems of the linted suites as an error
+func validate(l *suite.Linter) error {
+	if errs := l.Errors(); len(errs) > 0 {
+		return fmt.Errorf("invalid suite:\n%s", errs.Error())
+	}
+	return nil
+}
+
+// formatOf returns the format of the suite file, it is set by --format or detected by the file extension
+func formatOf(filePath string) suite.Format {
+	if suiteFormat != "" {
+		return suiteFormat
+	}
+	return suite.FormatFromPath(filePath)
+}
+
 func readFile(filePath string) ([]byte, error) {
 	f, err := os.Stat(filePath)
 	if err != nil {
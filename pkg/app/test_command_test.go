@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -88,6 +89,259 @@ func Test_TestCommand_Dir(t *testing.T) {
 	assert.Contains(t, out, "✓ [test.yaml] [local] it should print hello world")
 }
 
+func Test_TestCommand_Dir_Recursive(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(dir+"/sub", 0o755))
+	assert.Nil(t, os.WriteFile(dir+"/a_test.yaml", []byte("tests:\n  echo a:\n    exit-code: 0\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/sub/b_test.yaml", []byte("tests:\n  echo b:\n    skip: true\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/notes.txt", []byte("not a suite"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand(dir, TestCommandContext{Dir: true, Recursive: true, NoColor: true})
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "✓ [a_test.yaml] [local] echo a")
+	assert.Contains(t, out, "- [local] echo b, was skipped")
+	assert.Contains(t, out, "✓ [a_test.yaml] Count: 1, Failed: 0, Skipped: 0")
+	assert.Contains(t, out, "✓ [sub/b_test.yaml] Count: 1, Failed: 0, Skipped: 1")
+	assert.Contains(t, out, "Count: 2, Failed: 0, Skipped: 1")
+}
+
+func Test_TestCommand_Dir_InvalidSuite(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(dir+"/a.yaml", []byte("tests: ["), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/b.yaml", []byte("tests:\n  echo b:\n    exit-code: 0\n"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand(dir, TestCommandContext{Dir: true, NoColor: true})
+	})
+
+	assert.Equal(t, "Test suite failed, use --verbose for more detailed output", err.Error())
+	assert.Contains(t, out, "✗ [a.yaml] could not be loaded with error message:")
+	assert.Contains(t, out, "✓ [b.yaml] [local] echo b")
+	assert.Contains(t, out, "✓ [b.yaml] Count: 1, Failed: 0, Skipped: 0")
+}
+
+func Test_TestCommand_Dir_Parallel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on windows")
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		suite := "tests:\n  sleep " + name + ":\n    command: sleep 0.5\n    exit-code: 0\n  echo " + name + ":\n    exit-code: 0\n"
+		assert.Nil(t, os.WriteFile(dir+"/"+name+".yaml", []byte(suite), 0o644))
+	}
+
+	var err error
+	start := time.Now()
+	out := captureOutput(func() {
+		err = TestCommand(dir, TestCommandContext{Dir: true, Parallel: 3, NoColor: true})
+	})
+
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), 1200*time.Millisecond)
+	// the output of each file is grouped and printed in the order of the files
+	var results []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(l, "✓ [") && strings.Contains(l, "[local]") {
+			results = append(results, l)
+		}
+	}
+	assert.Equal(t, []string{
+		"✓ [a.yaml] [local] echo a",
+		"✓ [a.yaml] [local] sleep a",
+		"✓ [b.yaml] [local] echo b",
+		"✓ [b.yaml] [local] sleep b",
+		"✓ [c.yaml] [local] echo c",
+		"✓ [c.yaml] [local] sleep c",
+	}, results)
+	assert.Contains(t, out, "Count: 6, Failed: 0, Skipped: 0")
+}
+
+func Test_TestCommand_Dir_NegativeParallel(t *testing.T) {
+	err := TestCommand("testdata/", TestCommandContext{Dir: true, Parallel: -1})
+	assert.Equal(t, "Error: --parallel must not be negative, got -1", err.Error())
+}
+
+func Test_TestCommand_Dir_VerboseParallel(t *testing.T) {
+	err := TestCommand("testdata/", TestCommandContext{Dir: true, Parallel: 2, Verbose: true})
+	assert.Equal(t, "Error: --verbose can not be combined with --parallel greater than 1", err.Error())
+}
+
+func Test_TestCommand_Formats(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(dir+"/suite.json", []byte(`{"tests": {"echo json": {"stdout": "json"}}}`), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/suite.toml", []byte("[tests.\"echo toml\"]\nstdout = \"toml\"\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/toml.txt", []byte("[tests.\"echo txt\"]\nexit-code = 0\n"), 0o644))
+
+	out := captureOutput(func() {
+		assert.Nil(t, TestCommand(dir, TestCommandContext{Dir: true}))
+		assert.Nil(t, TestCommand(dir+"/toml.txt", TestCommandContext{Format: "toml"}))
+	})
+
+	assert.Contains(t, out, "✓ [suite.json] [local] echo json")
+	assert.Contains(t, out, "✓ [suite.toml] [local] echo toml")
+	assert.Contains(t, out, "✓ [local] echo txt")
+}
+
+func Test_TestCommand_InvalidFormat(t *testing.T) {
+	err := TestCommand("testdata/test.yaml", TestCommandContext{Format: "xml"})
+	assert.Equal(t, "Error: unknown format 'xml', expected yaml, json, toml, markdown or transcript", err.Error())
+}
+
+func Test_TestCommand_UpdateTranscript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("transcripts are executed by sh")
+	}
+
+	file := t.TempDir() + "/cli.t"
+	assert.Nil(t, os.WriteFile(file, []byte("  $ echo hello\n  wrong\n  $ echo 1.2.3\n  \\d.\\d.\\d (re)\n"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand(file, TestCommandContext{Update: true})
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, out, "Updated transcript "+file)
+
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "  $ echo hello\n  hello\n  $ echo 1.2.3\n  \\d.\\d.\\d (re)\n", string(content))
+
+	captureOutput(func() {
+		err = TestCommand(file, TestCommandContext{})
+	})
+	assert.Nil(t, err)
+}
+
+func Test_TestCommand_TranscriptMatchesExactly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("transcripts are executed by sh")
+	}
+
+	file := t.TempDir() + "/cli.t"
+	assert.Nil(t, os.WriteFile(file, []byte("  $ printf '  a\\n\\nb\\n'\n  a\n  \n  b\n"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand(file, TestCommandContext{Update: true, NoColor: true})
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, out, "-  a\n+a\n")
+
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "  $ printf '  a\\n\\nb\\n'\n    a\n  \n  b\n", string(content))
+
+	captureOutput(func() {
+		err = TestCommand(file, TestCommandContext{})
+	})
+	assert.Nil(t, err)
+}
+
+func Test_TestCommand_UpdateRequiresTranscript(t *testing.T) {
+	err := TestCommand("testdata/test.yaml", TestCommandContext{Update: true})
+	assert.Equal(t, "Error: --update is only supported for transcript files, testdata/test.yaml is a yaml suite", err.Error())
+
+	err = TestCommand("-", TestCommandContext{Update: true})
+	assert.Equal(t, "Error: --update is only supported for transcript files", err.Error())
+}
+
+func Test_TestCommand_InvalidSuite(t *testing.T) {
+	file := t.TempDir() + "/suite.yaml"
+	assert.Nil(t, os.WriteFile(file, []byte("tests:\n  echo hello:\n    skip: maybe\n    config:\n      timeout: soon\n"), 0o644))
+
+	err := TestCommand(file, TestCommandContext{})
+
+	assert.Equal(t, "Error invalid suite:\n"+
+		file+":3:11: expected true or false, got 'maybe'\n"+
+		file+":5:16: invalid timeout 'soon', expected a duration like 500ms, 10s or 1m", err.Error())
+}
+
+func Test_TestCommand_InvalidFilters(t *testing.T) {
+	err := TestCommand("testdata/test.yaml", TestCommandContext{Filters: []string{"(", "valid", "["}})
+
+	assert.Equal(t, "Error: invalid filter '(': error parsing regexp: missing closing ): `(`\n"+
+		"invalid filter '[': error parsing regexp: missing closing ]: `[`", err.Error())
+}
+
+func Test_TestCommand_Tags(t *testing.T) {
+	out := captureOutput(func() {
+		TestCommand("testdata/tags.yaml", TestCommandContext{Tags: []string{"smoke && !slow"}})
+	})
+
+	assert.Contains(t, out, "✓ [local] echo smoke #cli #smoke")
+	assert.NotContains(t, out, "echo slow")
+	assert.NotContains(t, out, "echo untagged")
+	assert.Contains(t, out, "Count: 1, Failed: 0")
+}
+
+func Test_TestCommand_ExcludeTags(t *testing.T) {
+	out := captureOutput(func() {
+		TestCommand("testdata/tags.yaml", TestCommandContext{ExcludeTags: []string{"slow"}})
+	})
+
+	assert.Contains(t, out, "✓ [local] echo smoke #cli #smoke")
+	assert.Contains(t, out, "✓ [local] echo untagged #cli")
+	assert.NotContains(t, out, "echo slow")
+}
+
+func Test_TestCommand_InvalidTagExpression(t *testing.T) {
+	err := TestCommand("testdata/tags.yaml", TestCommandContext{Tags: []string{"smoke &&"}})
+
+	assert.Equal(t, "Error: invalid tag expression 'smoke &&': expected a tag at the end", err.Error())
+}
+
+func Test_TestCommand_Dir_Filters(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(dir+"/a.yaml", []byte("tests:\n  echo login:\n    exit-code: 0\n  echo logout:\n    exit-code: 0\n"), 0o644))
+	assert.Nil(t, os.WriteFile(dir+"/b.yaml", []byte("tests:\n  echo login b:\n    exit-code: 0\n  echo other:\n    exit-code: 0\n"), 0o644))
+
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand(dir, TestCommandContext{Dir: true, NoColor: true, Filters: []string{"login", "a.yaml:logout"}, Excludes: []string{"b.yaml:login"}})
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "✓ [a.yaml] [local] echo login")
+	assert.Contains(t, out, "✓ [a.yaml] [local] echo logout")
+	assert.NotContains(t, out, "echo login b")
+	assert.NotContains(t, out, "echo other")
+	assert.Contains(t, out, "✓ [b.yaml] Count: 0, Failed: 0, Skipped: 0")
+}
+
+func Test_TestCommand_Nodes(t *testing.T) {
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand("testdata/test.yaml", TestCommandContext{Nodes: []string{"local"}})
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, "✓ [local] another")
+}
+
+func Test_TestCommand_EmptySelection(t *testing.T) {
+	var err error
+	out := captureOutput(func() {
+		err = TestCommand("testdata/test.yaml", TestCommandContext{Filters: []string{"another", "missing"}, Nodes: []string{"docker-host"}})
+	})
+
+	// another matches a test which is not executed on docker-host
+	assert.Equal(t, "could not find test with pattern: missing\n"+
+		"could not find test on node: docker-host\n"+
+		"Use --allow-empty if filters may not match any test", err.Error())
+	assert.Contains(t, out, "Count: 0, Failed: 0")
+
+	captureOutput(func() {
+		err = TestCommand("testdata/test.yaml", TestCommandContext{Filters: []string{"missing"}, AllowEmpty: true})
+	})
+	assert.Nil(t, err)
+}
+
 func Test_TestCommand_Dir_Err(t *testing.T) {
 	err := TestCommand("http://foo.com/bar", TestCommandContext{Dir: true})
 
@@ -178,12 +432,14 @@ func Test_ConvergeResults(t *testing.T) {
 		TestResults: []commanderRuntime.TestResult{},
 		Duration:    duration,
 		Failed:      1,
+		Skipped:     2,
 	}
 
 	result2 := commanderRuntime.Result{
 		TestResults: []commanderRuntime.TestResult{},
 		Duration:    duration,
 		Failed:      0,
+		Skipped:     1,
 	}
 
 	actual := convergeResults(result1, result2)
@@ -191,6 +447,7 @@ func Test_ConvergeResults(t *testing.T) {
 	expectDur, _ := time.ParseDuration("10s")
 	assert.Equal(t, expectDur, actual.Duration)
 	assert.Equal(t, 1, actual.Failed)
+	assert.Equal(t, 3, actual.Skipped)
 }
 
 func Test_TestCommand_InvalidDir(t *testing.T) {
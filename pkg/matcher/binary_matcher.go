@@ -0,0 +1,175 @@
+package matcher
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Supported checksum algorithms
+const (
+	ChecksumSHA256 = "sha256"
+	ChecksumMD5    = "md5"
+)
+
+// amount of hexdump rows which are displayed before and after the first difference
+const hexdumpContext = 2
+
+const hexdumpRowSize = 16
+
+// ByteSizeMatcher matches the amount of bytes of the got value
+type ByteSizeMatcher struct{}
+
+// Match compares the byte length of the got text with the expected size
+func (m ByteSizeMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	return matchSize(len(got.(string)), expected.(int))
+}
+
+func matchSize(size int, expected int) MatcherResult {
+	if size == expected {
+		return MatcherResult{Success: true}
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff:    fmt.Sprintf("Expected output to have a size of %d bytes, got %d bytes", expected, size),
+	}
+}
+
+// ChecksumMatcher matches the hex encoded checksum of the got value
+type ChecksumMatcher struct {
+	Algorithm string
+}
+
+// Match calculates the checksum of the got text and compares it case-insensitive with the expected checksum
+func (m ChecksumMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	h := newHash(m.Algorithm)
+	h.Write([]byte(got.(string)))
+	return matchChecksum(m.Algorithm, hex.EncodeToString(h.Sum(nil)), expected.(string))
+}
+
+func matchChecksum(algorithm string, sum string, expected string) MatcherResult {
+	if strings.EqualFold(sum, expected) {
+		return MatcherResult{Success: true}
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff: fmt.Sprintf(`Expected %s checksum
+
+%s
+
+to be equal to
+
+%s`, algorithm, sum, expected),
+	}
+}
+
+// BinaryFileMatcher matches the got value byte-for-byte against the contents of a file
+type BinaryFileMatcher struct{}
+
+// Match compares the got bytes with the file, on failure a hexdump around the first difference is displayed
+func (m BinaryFileMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	content, err := ReadFile(expected.(string))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	actual := []byte(got.(string))
+	if bytes.Equal(actual, content) {
+		return MatcherResult{Success: true}
+	}
+
+	offset := firstDifference(actual, content)
+	return MatcherResult{
+		Success: false,
+		Diff: fmt.Sprintf(`Expected output to be equal to file "%s", first difference at offset 0x%08x (%d)
+
+Got (%d bytes):
+
+%s
+
+Expected (%d bytes):
+
+%s`, expected, offset, offset, len(actual), hexdump(actual, offset), len(content), hexdump(content, offset)),
+	}
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case ChecksumSHA256:
+		return sha256.New()
+	case ChecksumMD5:
+		return md5.New()
+	default:
+		panic(fmt.Sprintf("Checksum algorithm '%s' does not exist!", algorithm))
+	}
+}
+
+func firstDifference(a []byte, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
+
+// hexdump renders the rows around the given offset like hexdump -C,
+// the row which contains the offset is marked with ">", an offset after the data is marked as the end of output
+func hexdump(data []byte, offset int) string {
+	row := offset / hexdumpRowSize
+	first := row - hexdumpContext
+	if first < 0 {
+		first = 0
+	}
+	last := row + hexdumpContext
+
+	var lines []string
+	for r := first; r <= last && r*hexdumpRowSize < len(data); r++ {
+		start := r * hexdumpRowSize
+		end := start + hexdumpRowSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		marker := " "
+		if r == row && offset < len(data) {
+			marker = ">"
+		}
+		lines = append(lines, fmt.Sprintf("%s %08x  %-48s |%s|", marker, start, hexBytes(data[start:end]), printable(data[start:end])))
+	}
+
+	if offset >= len(data) {
+		lines = append(lines, fmt.Sprintf("> %08x  <end of output>", offset))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hexBytes(data []byte) string {
+	var parts []string
+	for _, b := range data {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, " ")
+}
+
+func printable(data []byte) string {
+	var b strings.Builder
+	for _, c := range data {
+		if c >= 0x20 && c < 0x7f {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package matcher
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeMatcher_Match(t *testing.T) {
+	got := ByteSizeMatcher{}.Match("\x00\x01\x02", 3)
+	assert.True(t, got.Success)
+
+	got = ByteSizeMatcher{}.Match("\x00\x01", 3)
+	assert.False(t, got.Success)
+	assert.Equal(t, "Expected output to have a size of 3 bytes, got 2 bytes", got.Diff)
+}
+
+func TestChecksumMatcher_Match(t *testing.T) {
+	got := ChecksumMatcher{Algorithm: ChecksumSHA256}.Match("hello", "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824")
+	assert.True(t, got.Success)
+
+	got = ChecksumMatcher{Algorithm: ChecksumMD5}.Match("hello", "5d41402abc4b2a76b9719d911017c592")
+	assert.True(t, got.Success)
+
+	got = ChecksumMatcher{Algorithm: ChecksumMD5}.Match("hello\n", "5d41402abc4b2a76b9719d911017c592")
+	assert.False(t, got.Success)
+	assert.Equal(t, `Expected md5 checksum
+
+b1946ac92492d2347c6235b4d2611184
+
+to be equal to
+
+5d41402abc4b2a76b9719d911017c592`, got.Diff)
+}
+
+func TestBinaryFileMatcher_Match(t *testing.T) {
+	ReadFile = func(filename string) ([]byte, error) {
+		return []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x10"), nil
+	}
+	defer func() { ReadFile = os.ReadFile }()
+
+	got := BinaryFileMatcher{}.Match("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x10", "image.png")
+	assert.True(t, got.Success)
+
+	got = BinaryFileMatcher{}.Match("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x20", "image.png")
+	assert.False(t, got.Success)
+	assert.Equal(t, `Expected output to be equal to file "image.png", first difference at offset 0x00000013 (19)
+
+Got (20 bytes):
+
+  00000000  89 50 4e 47 0d 0a 1a 0a 00 00 00 0d 49 48 44 52  |.PNG........IHDR|
+> 00000010  00 00 00 20                                      |... |
+
+Expected (20 bytes):
+
+  00000000  89 50 4e 47 0d 0a 1a 0a 00 00 00 0d 49 48 44 52  |.PNG........IHDR|
+> 00000010  00 00 00 10                                      |....|`, got.Diff)
+}
+
+func TestBinaryFileMatcher_MatchShorterOutput(t *testing.T) {
+	ReadFile = func(filename string) ([]byte, error) {
+		return []byte("0123456789abcdef0123"), nil
+	}
+	defer func() { ReadFile = os.ReadFile }()
+
+	got := BinaryFileMatcher{}.Match("0123456789abcdef", "file.bin")
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "first difference at offset 0x00000010 (16)")
+	assert.Contains(t, got.Diff, "> 00000010  <end of output>")
+	assert.Contains(t, got.Diff, "> 00000010  30 31 32 33")
+}
+
+func Test_parseChecksum(t *testing.T) {
+	got, err := parseChecksum(16)("5D41402ABC4B2A76B9719D911017C592")
+	assert.Nil(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", got)
+
+	_, err = parseChecksum(16)("5d41")
+	assert.EqualError(t, err, "expected a hex encoded checksum with 32 characters, got 5d41")
+}
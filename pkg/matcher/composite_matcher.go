@@ -0,0 +1,209 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys of the operators which combine assertions
+const (
+	AnyOfKey = "any-of"
+	AllOfKey = "all-of"
+	NotKey   = "not"
+)
+
+// Check is a parsed assertion of an Expectation
+type Check struct {
+	Key      string
+	Expected interface{}
+	Matcher  Matcher
+}
+
+// Expectation is a set of assertions which all have to match, it is used as a branch of any-of, all-of and not
+type Expectation []Check
+
+// Match matches all checks, the diffs of all failed checks are joined
+func (e Expectation) Match(got string) MatcherResult {
+	var diffs []string
+	for _, c := range e {
+		if result := c.Matcher.Match(got, c.Expected); !result.Success {
+			diffs = append(diffs, fmt.Sprintf("%s:\n%s", c.Key, indent(result.Diff)))
+		}
+	}
+
+	if len(diffs) > 0 {
+		return MatcherResult{Success: false, Diff: strings.Join(diffs, "\n\n")}
+	}
+	return MatcherResult{Success: true}
+}
+
+// MarshalYAML converts the expectation back to a map of assertions
+func (e Expectation) MarshalYAML() (interface{}, error) {
+	values := make(map[string]interface{})
+	for _, c := range e {
+		values[c.Key] = c.Expected
+	}
+	return values, nil
+}
+
+// AnyOfMatcher matches if at least one of the expected branches matches
+type AnyOfMatcher struct{}
+
+// Match matches the branches in order until one matches, otherwise the diffs of all branches are displayed
+func (m AnyOfMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	branches := expected.([]Expectation)
+
+	var diffs []string
+	for i, b := range branches {
+		result := b.Match(got.(string))
+		if result.Success {
+			return MatcherResult{Success: true}
+		}
+		diffs = append(diffs, branchDiff(i, result))
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff:    fmt.Sprintf("Expected any of %d branches to match, none matched\n\n%s", len(branches), strings.Join(diffs, "\n\n")),
+	}
+}
+
+// AllOfMatcher matches if all expected branches match
+type AllOfMatcher struct{}
+
+// Match matches all branches, the diffs of all failed branches are displayed
+func (m AllOfMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	branches := expected.([]Expectation)
+
+	var diffs []string
+	for i, b := range branches {
+		if result := b.Match(got.(string)); !result.Success {
+			diffs = append(diffs, branchDiff(i, result))
+		}
+	}
+
+	if len(diffs) == 0 {
+		return MatcherResult{Success: true}
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff: fmt.Sprintf("Expected all of %d branches to match, %d failed\n\n%s",
+			len(branches), len(diffs), strings.Join(diffs, "\n\n")),
+	}
+}
+
+// NotMatcher matches if the expected branch does not match
+type NotMatcher struct{}
+
+// Match negates the result of the branch, it matches if at least one of its assertions fails
+func (m NotMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	branch := expected.(Expectation)
+	if !branch.Match(got.(string)).Success {
+		return MatcherResult{Success: true}
+	}
+
+	var checks []string
+	for _, c := range branch {
+		checks = append(checks, fmt.Sprintf("%s: %v", c.Key, c.Expected))
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff: fmt.Sprintf("Expected\n\n%s\n\nto not match, but all assertions matched:\n\n%s",
+			got, indent(strings.Join(checks, "\n"))),
+	}
+}
+
+// IsOperator returns true if the key is an operator which contains nested assertions
+func IsOperator(key string) bool {
+	return key == AnyOfKey || key == AllOfKey || key == NotKey
+}
+
+// ParseExpectation parses a map of assertions, which may contain nested operators, into an Expectation.
+// With raw output the raw parsers and matchers of the assertions are used.
+func ParseExpectation(value interface{}, raw bool) (Expectation, error) {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map of assertions, got %v", value)
+	}
+
+	keys := make(map[string]interface{})
+	for k, v := range values {
+		key := fmt.Sprint(k)
+		if _, ok := GetAssertion(key); !ok {
+			return nil, fmt.Errorf("assertion '%s' does not exist", key)
+		}
+		keys[key] = v
+	}
+
+	var e Expectation
+	// checks are matched in the order of registration like the top level assertions
+	for _, a := range Assertions() {
+		v, ok := keys[a.Key]
+		if !ok || v == nil {
+			continue
+		}
+
+		parse, m := a.Parse, a.Matcher
+		if raw && a.RawParse != nil {
+			parse = a.RawParse
+		}
+		if raw && a.RawMatcher != nil {
+			m = a.RawMatcher
+		}
+
+		parsed, err := parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", a.Key, err)
+		}
+		e = append(e, Check{Key: a.Key, Expected: parsed, Matcher: m})
+	}
+
+	if len(e) == 0 {
+		return nil, fmt.Errorf("expected at least one assertion, got %v", value)
+	}
+	return e, nil
+}
+
+// parseBranches parses a list of expectations for any-of and all-of
+func parseBranches(raw bool) ParseFunc {
+	return func(value interface{}) (interface{}, error) {
+		values, ok := value.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("expected a list of assertions, got %v", value)
+		}
+
+		var branches []Expectation
+		for i, v := range values {
+			e, err := ParseExpectation(v, raw)
+			if err != nil {
+				return nil, fmt.Errorf("branch %d: %s", i+1, err)
+			}
+			branches = append(branches, e)
+		}
+		return branches, nil
+	}
+}
+
+// parseNot parses the expectation of not
+func parseNot(raw bool) ParseFunc {
+	return func(value interface{}) (interface{}, error) {
+		return ParseExpectation(value, raw)
+	}
+}
+
+func branchDiff(i int, result MatcherResult) string {
+	return fmt.Sprintf("Branch %d failed:\n\n%s", i+1, indent(result.Diff))
+}
+
+// indent indents all lines of the text and removes surrounding blank lines
+func indent(text string) string {
+	lines := strings.Split(strings.Trim(text, "\n"), lineBreak)
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "    " + l
+		}
+	}
+	return strings.Join(lines, lineBreak)
+}
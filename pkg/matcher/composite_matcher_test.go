@@ -0,0 +1,118 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseExpectation(t *testing.T, value map[interface{}]interface{}) Expectation {
+	e, err := ParseExpectation(value, false)
+	assert.Nil(t, err)
+	return e
+}
+
+func TestAnyOfMatcher_Match(t *testing.T) {
+	branches := []Expectation{
+		parseExpectation(t, map[interface{}]interface{}{"contains": "linux"}),
+		parseExpectation(t, map[interface{}]interface{}{"contains": "darwin"}),
+	}
+
+	assert.True(t, AnyOfMatcher{}.Match("running on darwin", branches).Success)
+
+	result := AnyOfMatcher{}.Match("running on windows", branches)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, "Expected any of 2 branches to match, none matched")
+	assert.Contains(t, result.Diff, "Branch 1 failed:\n\n    contains:\n        Expected")
+	assert.Contains(t, result.Diff, "Branch 2 failed:")
+	assert.Contains(t, result.Diff, "        darwin")
+}
+
+func TestAllOfMatcher_Match(t *testing.T) {
+	branches := []Expectation{
+		parseExpectation(t, map[interface{}]interface{}{"contains": "hello"}),
+		parseExpectation(t, map[interface{}]interface{}{"line-count": 2}),
+	}
+
+	assert.True(t, AllOfMatcher{}.Match("hello\nworld", branches).Success)
+
+	result := AllOfMatcher{}.Match("hello", branches)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, "Expected all of 2 branches to match, 1 failed")
+	assert.Contains(t, result.Diff, "Branch 2 failed:\n\n    line-count:")
+	assert.NotContains(t, result.Diff, "Branch 1")
+}
+
+func TestNotMatcher_Match(t *testing.T) {
+	branch := parseExpectation(t, map[interface{}]interface{}{"contains": "error", "line-count": 1})
+
+	assert.True(t, NotMatcher{}.Match("no problem", branch).Success)
+	assert.True(t, NotMatcher{}.Match("error\nsecond line", branch).Success)
+
+	result := NotMatcher{}.Match("error", branch)
+	assert.False(t, result.Success)
+	assert.Equal(t, "Expected\n\nerror\n\nto not match, but all assertions matched:\n\n    contains: [error]\n    line-count: 1", result.Diff)
+}
+
+func TestNestedOperators(t *testing.T) {
+	branch := parseExpectation(t, map[interface{}]interface{}{
+		"any-of": []interface{}{
+			map[interface{}]interface{}{"exactly": "linux"},
+			map[interface{}]interface{}{
+				"all-of": []interface{}{
+					map[interface{}]interface{}{"contains": "darwin"},
+					map[interface{}]interface{}{"not": map[interface{}]interface{}{"contains": "error"}},
+				},
+			},
+		},
+	})
+
+	assert.True(t, branch.Match("linux").Success)
+	assert.True(t, branch.Match("darwin 21").Success)
+
+	result := branch.Match("darwin error")
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, "any-of:\n    Expected any of 2 branches to match, none matched")
+	assert.Contains(t, result.Diff, "    Branch 2 failed:\n\n        all-of:\n            Expected all of 2 branches to match, 1 failed")
+	assert.Contains(t, result.Diff, "to not match, but all assertions matched")
+}
+
+func TestParseExpectation_Raw(t *testing.T) {
+	e, err := ParseExpectation(map[interface{}]interface{}{"exactly": "hello\n"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello\n", e[0].Expected)
+
+	e, err = ParseExpectation(map[interface{}]interface{}{"exactly": "hello\n"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", e[0].Expected)
+}
+
+func TestParseExpectation_Order(t *testing.T) {
+	e := parseExpectation(t, map[interface{}]interface{}{"line-count": 1, "exactly": "a", "contains": "a"})
+
+	var keys []string
+	for _, c := range e {
+		keys = append(keys, c.Key)
+	}
+	assert.Equal(t, []string{"exactly", "contains", "line-count"}, keys)
+}
+
+func TestParseExpectation_Errors(t *testing.T) {
+	_, err := ParseExpectation("hello", false)
+	assert.EqualError(t, err, "expected a map of assertions, got hello")
+
+	_, err = ParseExpectation(map[interface{}]interface{}{"unknown": "hello"}, false)
+	assert.EqualError(t, err, "assertion 'unknown' does not exist")
+
+	_, err = ParseExpectation(map[interface{}]interface{}{"line-count": "many"}, false)
+	assert.EqualError(t, err, "line-count: expected an int, got many")
+
+	_, err = ParseExpectation(map[interface{}]interface{}{}, false)
+	assert.EqualError(t, err, "expected at least one assertion, got map[]")
+
+	_, err = parseBranches(false)([]interface{}{map[interface{}]interface{}{"contains": "a"}, "b"})
+	assert.EqualError(t, err, "branch 2: expected a map of assertions, got b")
+
+	_, err = parseBranches(false)(map[interface{}]interface{}{"contains": "a"})
+	assert.EqualError(t, err, "expected a list of assertions, got map[contains:a]")
+}
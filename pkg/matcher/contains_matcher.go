@@ -0,0 +1,299 @@
+package matcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maximum amount of positions which are displayed in a failure
+const maxDisplayedPositions = 10
+
+// ContainsCount expects a text to occur a specific amount of times, i.e. a warning which is printed exactly once.
+// Either Count or Min and Max can be set, occurrences do not overlap.
+type ContainsCount struct {
+	Text  string `yaml:"text"`
+	Count *int   `yaml:"count,omitempty"`
+	Min   *int   `yaml:"min,omitempty"`
+	Max   *int   `yaml:"max,omitempty"`
+}
+
+func (c ContainsCount) matches(count int) bool {
+	if c.Count != nil {
+		return count == *c.Count
+	}
+	return (c.Min == nil || count >= *c.Min) && (c.Max == nil || count <= *c.Max)
+}
+
+func (c ContainsCount) String() string {
+	switch {
+	case c.Count != nil:
+		return fmt.Sprintf("exactly %s", times(*c.Count))
+	case c.Min != nil && c.Max != nil:
+		return fmt.Sprintf("between %d and %s", *c.Min, times(*c.Max))
+	case c.Min != nil:
+		return fmt.Sprintf("at least %s", times(*c.Min))
+	default:
+		return fmt.Sprintf("at most %s", times(*c.Max))
+	}
+}
+
+// Position of an occurrence in the output, lines and columns start counting at 1
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// occurrences holds the amount of occurrences of a text and the positions of the first ones
+type occurrences struct {
+	Count     int
+	Positions []Position
+}
+
+// matchCount counts the occurrences of the expected text in the got text
+func matchCount(got string, expected ContainsCount) MatcherResult {
+	occ, _ := scanOccurrences(strings.NewReader(got), expected.Text)
+	return countResult(occ, expected, got)
+}
+
+// countResult creates the result of a counted contains, the lines of the occurrences are displayed if got is not empty
+func countResult(occ occurrences, expected ContainsCount, got string) MatcherResult {
+	if expected.matches(occ.Count) {
+		return MatcherResult{Success: true}
+	}
+
+	diff := fmt.Sprintf(`Expected "%s" to occur %s, found %s`, expected.Text, expected, times(occ.Count))
+	if occ.Count == 0 {
+		return MatcherResult{Success: false, Diff: diff}
+	}
+
+	var lines []string
+	for _, p := range occ.Positions {
+		if got == "" {
+			lines = append(lines, p.String())
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", p, lineAt(got, p.Offset)))
+	}
+	if more := occ.Count - len(occ.Positions); more > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more", more))
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff:    fmt.Sprintf("%s:\n\n%s", diff, strings.Join(lines, "\n")),
+	}
+}
+
+// ContainsInOrderMatcher tests if the expected texts appear in the given order
+type ContainsInOrderMatcher struct{}
+
+// Match searches each text after the end of the previous one
+func (m ContainsInOrderMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	text := got.(string)
+	texts := expected.([]string)
+
+	from := 0
+	for i, t := range texts {
+		idx := strings.Index(text[from:], t)
+		if idx >= 0 {
+			from += idx + len(t)
+			continue
+		}
+
+		reason := fmt.Sprintf(`"%s" was not found`, t)
+		if i > 0 {
+			prev := positionAt(text, from-len(texts[i-1]))
+			reason = fmt.Sprintf(`"%s" was not found after "%s" at %s`, t, texts[i-1], prev)
+			if before := strings.Index(text, t); before >= 0 {
+				reason = fmt.Sprintf(`"%s" was found at %s, before "%s" at %s`, t, positionAt(text, before), texts[i-1], prev)
+			}
+		}
+
+		return MatcherResult{
+			Success: false,
+			Diff: fmt.Sprintf(`
+Expected
+
+%s
+
+to contain in order
+
+%s
+
+%s
+`, text, strings.Join(texts, "\n"), reason),
+		}
+	}
+
+	return MatcherResult{Success: true}
+}
+
+// scanOccurrences counts the non-overlapping occurrences of the text in the reader and keeps the positions of the first ones.
+// The reader is processed in chunks which overlap by the length of the text.
+func scanOccurrences(r io.Reader, text string) (occurrences, error) {
+	var occ occurrences
+	needle := []byte(text)
+	if len(needle) == 0 {
+		return occ, nil
+	}
+
+	var window []byte
+	// offset of the first byte of the window in the output
+	base := 0
+	// line and line start of the byte at pos in the window
+	line, lineStart, pos := 1, 0, 0
+	advance := func(to int) {
+		for i := pos; i < to; i++ {
+			if window[i] == '\n' {
+				line++
+				lineStart = base + i + 1
+			}
+		}
+		pos = to
+	}
+
+	buf := make([]byte, streamChunkSize)
+	search := 0
+	for {
+		n, err := r.Read(buf)
+		window = append(window, buf[:n]...)
+
+		for {
+			idx := bytes.Index(window[search:], needle)
+			if idx < 0 {
+				break
+			}
+			idx += search
+			advance(idx)
+
+			occ.Count++
+			if len(occ.Positions) < maxDisplayedPositions {
+				occ.Positions = append(occ.Positions, Position{Offset: base + idx, Line: line, Column: base + idx - lineStart + 1})
+			}
+			search = idx + len(needle)
+		}
+
+		// keep the end of the window in case the text is split between two chunks
+		keep := len(window) - (len(needle) - 1)
+		if keep < search {
+			keep = search
+		}
+		if keep > 0 {
+			advance(keep)
+			window = append(window[:0], window[keep:]...)
+			base += keep
+			// keep is never before the end of the last occurrence
+			pos, search = 0, 0
+		}
+
+		if err == io.EOF {
+			return occ, nil
+		}
+		if err != nil {
+			return occ, err
+		}
+	}
+}
+
+// positionAt returns the position of the offset in the text
+func positionAt(text string, offset int) Position {
+	before := text[:offset]
+	return Position{
+		Offset: offset,
+		Line:   strings.Count(before, lineBreak) + 1,
+		Column: offset - (strings.LastIndex(before, lineBreak) + 1) + 1,
+	}
+}
+
+// lineAt returns the line which contains the offset
+func lineAt(text string, offset int) string {
+	start := strings.LastIndex(text[:offset], lineBreak) + 1
+	end := strings.Index(text[offset:], lineBreak)
+	if end < 0 {
+		return text[start:]
+	}
+	return text[start : offset+end]
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+// parseContains parses a list of texts and counted texts, see ContainsCount
+func parseContains(value interface{}) (interface{}, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	var list []interface{}
+	for _, v := range values {
+		if _, ok := v.(map[interface{}]interface{}); !ok {
+			s, err := ParseString(v)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, s)
+			continue
+		}
+
+		c, err := parseContainsCount(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+func parseContainsCount(value interface{}) (ContainsCount, error) {
+	c := ContainsCount{}
+	if err := Decode(value, &c); err != nil {
+		return c, err
+	}
+
+	if c.Text == "" {
+		return c, fmt.Errorf("expected a text to count, got %v", value)
+	}
+	if c.Count == nil && c.Min == nil && c.Max == nil {
+		return c, fmt.Errorf("expected count, min or max for text '%s'", c.Text)
+	}
+	if c.Count != nil && (c.Min != nil || c.Max != nil) {
+		return c, fmt.Errorf("count can not be combined with min or max for text '%s'", c.Text)
+	}
+	for _, n := range []*int{c.Count, c.Min, c.Max} {
+		if n != nil && *n < 0 {
+			return c, fmt.Errorf("expected a positive amount for text '%s', got %d", c.Text, *n)
+		}
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return c, fmt.Errorf("min %d is greater than max %d for text '%s'", *c.Min, *c.Max, c.Text)
+	}
+	return c, nil
+}
+
+// toList converts a list of expected values, as used by EachMatcher, to a generic list
+func toList(expected interface{}) []interface{} {
+	switch v := expected.(type) {
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	case []interface{}:
+		return v
+	default:
+		panic(fmt.Sprintf("Expected a list, got %v", expected))
+	}
+}
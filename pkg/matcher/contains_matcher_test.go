@@ -0,0 +1,132 @@
+package matcher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestContainsMatcher_Count(t *testing.T) {
+	got := "WARN disk\nINFO ok\nWARN memory"
+
+	assert.True(t, ContainsMatcher{}.Match(got, ContainsCount{Text: "WARN", Count: intPtr(2)}).Success)
+	assert.True(t, ContainsMatcher{}.Match(got, ContainsCount{Text: "WARN", Min: intPtr(1)}).Success)
+	assert.True(t, ContainsMatcher{}.Match(got, ContainsCount{Text: "ERROR", Max: intPtr(0)}).Success)
+	assert.True(t, ContainsMatcher{}.Match(got, ContainsCount{Text: "INFO", Min: intPtr(1), Max: intPtr(1)}).Success)
+
+	result := ContainsMatcher{}.Match(got, ContainsCount{Text: "WARN", Count: intPtr(1)})
+	assert.False(t, result.Success)
+	assert.Equal(t, `Expected "WARN" to occur exactly 1 time, found 2 times:
+
+line 1, column 1: WARN disk
+line 3, column 1: WARN memory`, result.Diff)
+}
+
+func TestContainsMatcher_CountNotFound(t *testing.T) {
+	result := ContainsMatcher{}.Match("INFO ok", ContainsCount{Text: "WARN", Min: intPtr(1), Max: intPtr(3)})
+	assert.False(t, result.Success)
+	assert.Equal(t, `Expected "WARN" to occur between 1 and 3 times, found 0 times`, result.Diff)
+}
+
+func TestContainsMatcher_CountLimitsPositions(t *testing.T) {
+	got := strings.Repeat("a\n", 15)
+
+	result := ContainsMatcher{}.Match(got, ContainsCount{Text: "a", Max: intPtr(1)})
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, `Expected "a" to occur at most 1 time, found 15 times`)
+	assert.Contains(t, result.Diff, "line 10, column 1: a\n... and 5 more")
+}
+
+func TestEachMatcher_WithCounts(t *testing.T) {
+	m := EachMatcher{ContainsMatcher{}}
+	assert.True(t, m.Match("WARN once", []interface{}{"once", ContainsCount{Text: "WARN", Count: intPtr(1)}}).Success)
+	assert.False(t, m.Match("WARN once", []interface{}{"twice", ContainsCount{Text: "WARN", Count: intPtr(1)}}).Success)
+}
+
+func TestContainsMatcher_MatchStreamCount(t *testing.T) {
+	output := strings.Repeat("x", streamChunkSize-2) + "WARN\n" + strings.Repeat("y", streamChunkSize) + "\nWARN"
+
+	result := ContainsMatcher{}.MatchStream(openString(output), "truncated", ContainsCount{Text: "WARN", Count: intPtr(2)})
+	assert.True(t, result.Success)
+
+	result = ContainsMatcher{}.MatchStream(openString(output), "truncated", ContainsCount{Text: "WARN", Count: intPtr(1)})
+	assert.False(t, result.Success)
+	assert.Equal(t, `Expected "WARN" to occur exactly 1 time, found 2 times:
+
+line 1, column 65535
+line 3, column 1`, result.Diff)
+}
+
+func Test_scanOccurrences(t *testing.T) {
+	occ, err := scanOccurrences(strings.NewReader("aaaa\nb aa"), "aa")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, occ.Count)
+	assert.Equal(t, []Position{
+		{Offset: 0, Line: 1, Column: 1},
+		{Offset: 2, Line: 1, Column: 3},
+		{Offset: 7, Line: 2, Column: 3},
+	}, occ.Positions)
+}
+
+func TestContainsInOrderMatcher_Match(t *testing.T) {
+	got := "Step 1\nStep 2\nStep 3"
+
+	assert.True(t, ContainsInOrderMatcher{}.Match(got, []string{"Step 1", "Step 3"}).Success)
+	assert.True(t, ContainsInOrderMatcher{}.Match(got, []string{"Step", "Step", "Step"}).Success)
+	assert.False(t, ContainsInOrderMatcher{}.Match(got, []string{"Step", "Step", "Step", "Step"}).Success)
+}
+
+func TestContainsInOrderMatcher_WrongOrder(t *testing.T) {
+	result := ContainsInOrderMatcher{}.Match("Step 2\nStep 1", []string{"Step 1", "Step 2"})
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, "to contain in order\n\nStep 1\nStep 2")
+	assert.Contains(t, result.Diff, `"Step 2" was found at line 1, column 1, before "Step 1" at line 2, column 1`)
+}
+
+func TestContainsInOrderMatcher_NotFound(t *testing.T) {
+	result := ContainsInOrderMatcher{}.Match("Step 1", []string{"Step 0", "Step 1"})
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, `"Step 0" was not found`)
+
+	result = ContainsInOrderMatcher{}.Match("Step 1", []string{"Step 1", "Step 2"})
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Diff, `"Step 2" was not found after "Step 1" at line 1, column 1`)
+}
+
+func Test_parseContains(t *testing.T) {
+	got, err := parseContains([]interface{}{
+		"hello",
+		map[interface{}]interface{}{"text": "WARN", "count": 1},
+		map[interface{}]interface{}{"text": "INFO", "min": 1, "max": 2},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		"hello",
+		ContainsCount{Text: "WARN", Count: intPtr(1)},
+		ContainsCount{Text: "INFO", Min: intPtr(1), Max: intPtr(2)},
+	}, got)
+}
+
+func Test_parseContains_Invalid(t *testing.T) {
+	tests := map[string]map[interface{}]interface{}{
+		"expected a text to count, got map[count:1]":                {"count": 1},
+		"expected count, min or max for text 'WARN'":                {"text": "WARN"},
+		"count can not be combined with min or max for text 'WARN'": {"text": "WARN", "count": 1, "min": 1},
+		"expected a positive amount for text 'WARN', got -1":        {"text": "WARN", "min": -1},
+		"min 2 is greater than max 1 for text 'WARN'":               {"text": "WARN", "min": 2, "max": 1},
+	}
+
+	for msg, value := range tests {
+		_, err := parseContains([]interface{}{value})
+		assert.EqualError(t, err, msg)
+	}
+
+	_, err := parseContains([]interface{}{map[interface{}]interface{}{"text": "WARN", "times": 1}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,99 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExitCodeExpectation represents all allowed exit codes of a command.
+// A code is allowed if it is listed in Codes or Ranges and not listed in Not.
+// If neither Codes nor Ranges are set every code which is not listed in Not is allowed.
+type ExitCodeExpectation struct {
+	Any    bool
+	Codes  []int
+	Ranges []ExitCodeRange
+	Not    []int
+}
+
+// ExitCodeRange represents an inclusive range of exit codes
+type ExitCodeRange struct {
+	Min int
+	Max int
+}
+
+// Allows checks if the given exit code satisfies the expectation
+func (e ExitCodeExpectation) Allows(code int) bool {
+	if e.Any {
+		return true
+	}
+
+	for _, n := range e.Not {
+		if n == code {
+			return false
+		}
+	}
+
+	if len(e.Codes) == 0 && len(e.Ranges) == 0 {
+		return true
+	}
+
+	for _, c := range e.Codes {
+		if c == code {
+			return true
+		}
+	}
+
+	for _, r := range e.Ranges {
+		if code >= r.Min && code <= r.Max {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns a human readable representation, i.e. "1, 3-5, not 4"
+func (e ExitCodeExpectation) String() string {
+	if e.Any {
+		return "any"
+	}
+
+	var parts []string
+	for _, c := range e.Codes {
+		parts = append(parts, fmt.Sprintf("%d", c))
+	}
+
+	for _, r := range e.Ranges {
+		parts = append(parts, fmt.Sprintf("%d-%d", r.Min, r.Max))
+	}
+
+	for _, n := range e.Not {
+		parts = append(parts, fmt.Sprintf("not %d", n))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ExitCodeMatcher matches an exit code against an ExitCodeExpectation
+type ExitCodeMatcher struct{}
+
+// Match checks if the got exit code is allowed by the expectation
+func (m ExitCodeMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	exp := expected.(ExitCodeExpectation)
+	if exp.Allows(got.(int)) {
+		return MatcherResult{Success: true}
+	}
+
+	return MatcherResult{
+		Success: false,
+		Diff: fmt.Sprintf(`
+Expected exit code
+
+%d
+
+to match
+
+%s
+`, got, exp),
+	}
+}
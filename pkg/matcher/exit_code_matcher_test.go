@@ -0,0 +1,48 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewMatcher_ExitCode(t *testing.T) {
+	got := NewMatcher(ExitCode)
+	assert.IsType(t, ExitCodeMatcher{}, got)
+}
+
+func TestExitCodeExpectation_Allows(t *testing.T) {
+	oneOf := ExitCodeExpectation{Codes: []int{1, 2}}
+	assert.True(t, oneOf.Allows(2))
+	assert.False(t, oneOf.Allows(0))
+
+	ranges := ExitCodeExpectation{Ranges: []ExitCodeRange{{Min: 1, Max: 125}}, Not: []int{3}}
+	assert.True(t, ranges.Allows(1))
+	assert.True(t, ranges.Allows(125))
+	assert.False(t, ranges.Allows(3))
+	assert.False(t, ranges.Allows(126))
+
+	notZero := ExitCodeExpectation{Not: []int{0}}
+	assert.True(t, notZero.Allows(127))
+	assert.False(t, notZero.Allows(0))
+
+	any := ExitCodeExpectation{Any: true}
+	assert.True(t, any.Allows(255))
+}
+
+func TestExitCodeExpectation_String(t *testing.T) {
+	e := ExitCodeExpectation{Codes: []int{1}, Ranges: []ExitCodeRange{{Min: 3, Max: 5}}, Not: []int{4}}
+	assert.Equal(t, "1, 3-5, not 4", e.String())
+	assert.Equal(t, "any", ExitCodeExpectation{Any: true}.String())
+}
+
+func TestExitCodeMatcher_Match(t *testing.T) {
+	m := ExitCodeMatcher{}
+
+	got := m.Match(1, ExitCodeExpectation{Not: []int{0}})
+	assert.True(t, got.Success)
+
+	got = m.Match(0, ExitCodeExpectation{Not: []int{0}})
+	assert.False(t, got.Success)
+	assert.Equal(t, "\nExpected exit code\n\n0\n\nto match\n\nnot 0\n", got.Diff)
+}
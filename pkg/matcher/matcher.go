@@ -23,6 +23,19 @@ const (
 	JSON        = "json"
 	XML         = "xml"
 	File        = "file"
+	Table       = "table"
+	ExitCode    = "exitcode"
+	LineCount   = "linecount"
+	Lines       = "lines"
+	ByteSize    = "bytesize"
+	Checksum    = "checksum"
+	BinaryFile  = "binaryfile"
+	// ContainsInOrder matcher type
+	ContainsInOrder = "containsinorder"
+	AnyOf           = "anyof"
+	AllOf           = "allof"
+	Not             = "not"
+	Transcript      = "transcript"
 )
 
 var (
@@ -33,32 +46,36 @@ var (
 	_ Matcher = (*JSONMatcher)(nil)
 	_ Matcher = (*XMLMatcher)(nil)
 	_ Matcher = (*FileMatcher)(nil)
+	_ Matcher = (*TableMatcher)(nil)
+	_ Matcher = (*ExitCodeMatcher)(nil)
+	_ Matcher = (*LineCountMatcher)(nil)
+	_ Matcher = (*LinesMatcher)(nil)
+	_ Matcher = (*EachMatcher)(nil)
+	_ Matcher = (*ByteSizeMatcher)(nil)
+	_ Matcher = (*ChecksumMatcher)(nil)
+	_ Matcher = (*BinaryFileMatcher)(nil)
+	_ Matcher = (*ContainsInOrderMatcher)(nil)
+	_ Matcher = (*AnyOfMatcher)(nil)
+	_ Matcher = (*AllOfMatcher)(nil)
+	_ Matcher = (*NotMatcher)(nil)
 )
 
+const lineBreak = "\n"
+
 // The function used to open files when necessary for matching
 // Allows the file IO to be overridden during tests
 var ReadFile = os.ReadFile
 
-// NewMatcher creates a new matcher by type
+// NewMatcher creates a new matcher by type, see Register to add new matchers
 func NewMatcher(matcher string) Matcher {
-	switch matcher {
-	case Text:
-		return TextMatcher{}
-	case Contains:
-		return ContainsMatcher{}
-	case Equal:
-		return EqualMatcher{}
-	case NotContains:
-		return NotContainsMatcher{}
-	case JSON:
-		return JSONMatcher{}
-	case XML:
-		return XMLMatcher{}
-	case File:
-		return FileMatcher{}
-	default:
+	registryMu.RLock()
+	factory, ok := matchers[matcher]
+	registryMu.RUnlock()
+
+	if !ok {
 		panic(fmt.Sprintf("Validator '%s' does not exist!", matcher))
 	}
+	return factory()
 }
 
 // Matcher interface which is implemented by all matchers
@@ -97,8 +114,12 @@ func (m TextMatcher) Match(got interface{}, expected interface{}) MatcherResult
 // ContainsMatcher tests if the expected value is in the got variable
 type ContainsMatcher struct{}
 
-// Match matches on the given values
+// Match matches on the given values, a ContainsCount expects the text to occur a specific amount of times
 func (m ContainsMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	if c, ok := expected.(ContainsCount); ok {
+		return matchCount(got.(string), c)
+	}
+
 	result := strings.Contains(got.(string), expected.(string))
 
 	diff := `
@@ -245,15 +266,21 @@ to be equal to
 }
 
 // FileMatcher matches output captured from stdout or stderr
-// against the contents of a file
-type FileMatcher struct{}
+// against the contents of a file.
+// If Raw is set the contents are compared byte-for-byte, otherwise surrounding whitespace is removed.
+type FileMatcher struct {
+	Raw bool
+}
 
 func (m FileMatcher) Match(got interface{}, expected interface{}) MatcherResult {
 	expectedText, err := ReadFile(expected.(string))
 	if err != nil {
 		panic(err.Error())
 	}
-	expectedString := strings.TrimSpace(strings.ReplaceAll(string(expectedText), "\r\n", "\n"))
+	expectedString := string(expectedText)
+	if !m.Raw {
+		expectedString = strings.TrimSpace(strings.ReplaceAll(expectedString, "\r\n", "\n"))
+	}
 
 	result := got == expectedString
 
@@ -271,3 +298,75 @@ func (m FileMatcher) Match(got interface{}, expected interface{}) MatcherResult
 		Success: result,
 	}
 }
+
+// LineCountMatcher matches the amount of lines of the got value
+type LineCountMatcher struct{}
+
+// Match counts the lines of the got text and compares it to the expected count
+func (m LineCountMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	text := got.(string)
+	count := strings.Count(text, lineBreak) + 1
+
+	if text == "" {
+		count = 0
+	}
+
+	return EqualMatcher{}.Match(count, expected)
+}
+
+// LinesMatcher matches specific lines of the got value, line numbers start counting at 1
+type LinesMatcher struct{}
+
+// Match compares each expected line by its line number
+func (m LinesMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	actualLines := strings.Split(got.(string), lineBreak)
+	result := MatcherResult{Success: true}
+
+	for key, expectedLine := range expected.(map[int]string) {
+		// line number 0 or below 0, they are rejected by the parser but the matcher can be used directly
+		if key <= 0 {
+			return MatcherResult{
+				Success: false,
+				Diff:    fmt.Sprintf("Invalid line number %d, line numbers start at 1", key),
+			}
+		}
+
+		// line number exceeds result set
+		if key > len(actualLines) {
+			return MatcherResult{
+				Success: false,
+				Diff: fmt.Sprintf(
+					"Line number %d does not exists in result: \n\n%s",
+					key,
+					strings.Join(actualLines, "\n"),
+				),
+			}
+		}
+
+		if result = (EqualMatcher{}).Match(actualLines[key-1], expectedLine); !result.Success {
+			return result
+		}
+	}
+
+	return result
+}
+
+// EachMatcher matches each element of the expected list with the wrapped matcher
+type EachMatcher struct {
+	Matcher Matcher
+}
+
+// Match matches all elements, the diffs of all failed elements are joined
+func (m EachMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	var diffs []string
+	for _, e := range toList(expected) {
+		if result := m.Matcher.Match(got, e); !result.Success {
+			diffs = append(diffs, result.Diff)
+		}
+	}
+
+	if len(diffs) > 0 {
+		return MatcherResult{Success: false, Diff: strings.Join(diffs, "\n")}
+	}
+	return MatcherResult{Success: true}
+}
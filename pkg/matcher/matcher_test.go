@@ -2,6 +2,7 @@ package matcher
 
 import (
 	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
 )
 
@@ -194,6 +195,22 @@ func TestFileMatcher_Match(t *testing.T) {
 	assert.Equal(t, "", got.Diff)
 }
 
+func TestFileMatcher_MatchRaw(t *testing.T) {
+	ReadFile = func(filename string) ([]byte, error) {
+		return []byte("line one\r\nline two\n"), nil
+	}
+	defer func() { ReadFile = os.ReadFile }()
+
+	got := FileMatcher{Raw: true}.Match("line one\r\nline two\n", "fake.txt")
+	assert.True(t, got.Success)
+
+	got = FileMatcher{Raw: true}.Match("line one\nline two", "fake.txt")
+	assert.False(t, got.Success)
+
+	got = FileMatcher{}.Match("line one\nline two", "fake.txt")
+	assert.True(t, got.Success)
+}
+
 func TestFileMatcher_ValidateFails(t *testing.T) {
 	ReadFile = func(filename string) ([]byte, error) {
 		return []byte("line one\nline two"), nil
@@ -0,0 +1,319 @@
+package matcher
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ParseFunc converts the raw value of an assertion, as decoded from the suite, into the expected value
+// which is passed to the matcher
+type ParseFunc func(value interface{}) (interface{}, error)
+
+// Assertion describes an assertion type which can be used in the stdout and stderr sections of a suite
+type Assertion struct {
+	// Key is the key of the assertion in the suite, i.e. contains
+	Key string
+	// Parse converts the raw suite value into the expected value
+	Parse ParseFunc
+	// Matcher matches the output against the parsed expected value
+	Matcher Matcher
+	// RawParse and RawMatcher are used instead of Parse and Matcher for tests with raw output, if they are set
+	RawParse   ParseFunc
+	RawMatcher Matcher
+	// Binary assertions match the output byte-for-byte, tests which use them are executed with raw output
+	Binary bool
+}
+
+var (
+	registryMu sync.RWMutex
+	matchers   = map[string]func() Matcher{}
+	assertions []Assertion
+)
+
+func init() {
+	Register(Text, func() Matcher { return TextMatcher{} })
+	Register(Contains, func() Matcher { return ContainsMatcher{} })
+	Register(Equal, func() Matcher { return EqualMatcher{} })
+	Register(NotContains, func() Matcher { return NotContainsMatcher{} })
+	Register(JSON, func() Matcher { return JSONMatcher{} })
+	Register(XML, func() Matcher { return XMLMatcher{} })
+	Register(File, func() Matcher { return FileMatcher{} })
+	Register(Table, func() Matcher { return TableMatcher{} })
+	Register(ExitCode, func() Matcher { return ExitCodeMatcher{} })
+	Register(LineCount, func() Matcher { return LineCountMatcher{} })
+	Register(Lines, func() Matcher { return LinesMatcher{} })
+	Register(ByteSize, func() Matcher { return ByteSizeMatcher{} })
+	Register(Checksum, func() Matcher { return ChecksumMatcher{Algorithm: ChecksumSHA256} })
+	Register(BinaryFile, func() Matcher { return BinaryFileMatcher{} })
+	Register(ContainsInOrder, func() Matcher { return ContainsInOrderMatcher{} })
+	Register(AnyOf, func() Matcher { return AnyOfMatcher{} })
+	Register(AllOf, func() Matcher { return AllOfMatcher{} })
+	Register(Not, func() Matcher { return NotMatcher{} })
+	Register(Transcript, func() Matcher { return TranscriptMatcher{} })
+
+	// The order of registration defines the order in which the assertions are validated
+	RegisterAssertion(Assertion{Key: "exactly", Parse: ParseString, Matcher: TextMatcher{}, RawParse: ParseRawString})
+	RegisterAssertion(Assertion{Key: "contains", Parse: parseContains, Matcher: EachMatcher{ContainsMatcher{}}})
+	RegisterAssertion(Assertion{Key: "line-count", Parse: ParseInt, Matcher: LineCountMatcher{}})
+	RegisterAssertion(Assertion{Key: "lines", Parse: parseLines, Matcher: LinesMatcher{}})
+	RegisterAssertion(Assertion{Key: "not-contains", Parse: ParseStringList, Matcher: EachMatcher{NotContainsMatcher{}}})
+	RegisterAssertion(Assertion{Key: "json", Parse: ParseStringMap, Matcher: JSONMatcher{}})
+	RegisterAssertion(Assertion{Key: "xml", Parse: ParseStringMap, Matcher: XMLMatcher{}})
+	RegisterAssertion(Assertion{Key: "file", Parse: ParseString, Matcher: FileMatcher{}, RawMatcher: FileMatcher{Raw: true}})
+	RegisterAssertion(Assertion{Key: "table", Parse: parseTable, Matcher: TableMatcher{}})
+	RegisterAssertion(Assertion{Key: "byte-size", Parse: ParseInt, Matcher: ByteSizeMatcher{}, Binary: true})
+	RegisterAssertion(Assertion{Key: "sha256", Parse: parseChecksum(sha256.Size), Matcher: ChecksumMatcher{Algorithm: ChecksumSHA256}, Binary: true})
+	RegisterAssertion(Assertion{Key: "md5", Parse: parseChecksum(md5.Size), Matcher: ChecksumMatcher{Algorithm: ChecksumMD5}, Binary: true})
+	RegisterAssertion(Assertion{Key: "binary-file", Parse: ParseString, Matcher: BinaryFileMatcher{}, Binary: true})
+	RegisterAssertion(Assertion{Key: "contains-in-order", Parse: ParseStringList, Matcher: ContainsInOrderMatcher{}})
+	RegisterAssertion(Assertion{Key: AnyOfKey, Parse: parseBranches(false), RawParse: parseBranches(true), Matcher: AnyOfMatcher{}})
+	RegisterAssertion(Assertion{Key: AllOfKey, Parse: parseBranches(false), RawParse: parseBranches(true), Matcher: AllOfMatcher{}})
+	RegisterAssertion(Assertion{Key: NotKey, Parse: parseNot(false), RawParse: parseNot(true), Matcher: NotMatcher{}})
+	RegisterAssertion(Assertion{Key: "transcript", Parse: parseTranscript, Matcher: TranscriptMatcher{}, RawMatcher: TranscriptMatcher{Raw: true}})
+}
+
+// Register adds a matcher which can be created by its name with NewMatcher.
+// It panics if a matcher with the same name was already registered.
+// The returned function removes the matcher again, i.e. in the cleanup of a test.
+func Register(name string, factory func() Matcher) func() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := matchers[name]; ok {
+		panic(fmt.Sprintf("Matcher '%s' is already registered", name))
+	}
+	matchers[name] = factory
+	return func() { unregister(name) }
+}
+
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(matchers, name)
+}
+
+// RegisterAssertion adds an assertion type which can be used in the stdout and stderr sections of a suite.
+// It panics if an assertion with the same key was already registered.
+// The returned function removes the assertion again, i.e. in the cleanup of a test.
+func RegisterAssertion(a Assertion) func() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range assertions {
+		if r.Key == a.Key {
+			panic(fmt.Sprintf("Assertion '%s' is already registered", a.Key))
+		}
+	}
+	assertions = append(assertions, a)
+	return func() { unregisterAssertion(a.Key) }
+}
+
+func unregisterAssertion(key string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i, a := range assertions {
+		if a.Key == key {
+			assertions = append(assertions[:i:i], assertions[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetAssertion returns the assertion registered with the given key
+func GetAssertion(key string) (Assertion, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, a := range assertions {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return Assertion{}, false
+}
+
+// Assertions returns all registered assertions in the order of their registration
+func Assertions() []Assertion {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Assertion{}, assertions...)
+}
+
+// ParseString parses a scalar value as a string and removes trailing blank lines
+func ParseString(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return nil, fmt.Errorf("expected a string, got %v", value)
+	}
+	return toString(value), nil
+}
+
+// ParseRawString parses a scalar value as a string and keeps all whitespace
+func ParseRawString(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return nil, fmt.Errorf("expected a string, got %v", value)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// ParseStringList parses a single value or a list of values as a list of strings
+func ParseStringList(value interface{}) (interface{}, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	var list []string
+	for _, v := range values {
+		s, err := ParseString(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s.(string))
+	}
+	return list, nil
+}
+
+// ParseInt parses an integer value
+func ParseInt(value interface{}) (interface{}, error) {
+	i, ok := value.(int)
+	if !ok {
+		return nil, fmt.Errorf("expected an int, got %v", value)
+	}
+	return i, nil
+}
+
+// ParseStringMap parses a map with string keys and values
+func ParseStringMap(value interface{}) (interface{}, error) {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map, got %v", value)
+	}
+
+	m := make(map[string]string)
+	for k, v := range values {
+		s, err := ParseString(v)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = s.(string)
+	}
+	return m, nil
+}
+
+// UnknownKeyError is returned by Decode if the value contains a key which is not a field of the struct
+type UnknownKeyError struct {
+	Key string
+}
+
+func (e UnknownKeyError) Error() string {
+	return fmt.Sprintf("unknown key '%s'", e.Key)
+}
+
+var (
+	unknownFieldRegex = regexp.MustCompile(`^line \d+: field (.+) not found in type `)
+	errorLineRegex    = regexp.MustCompile(`^line \d+: `)
+)
+
+// Decode parses the value into the given struct by re-encoding it, unknown keys are rejected with an UnknownKeyError.
+// Line numbers are removed from errors, they refer to the re-encoded value and not to the suite.
+func Decode(value interface{}, out interface{}) error {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	err = yaml.UnmarshalStrict(content, out)
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return err
+	}
+
+	var messages []string
+	for _, e := range typeErr.Errors {
+		if m := unknownFieldRegex.FindStringSubmatch(e); m != nil {
+			return UnknownKeyError{Key: m[1]}
+		}
+		messages = append(messages, errorLineRegex.ReplaceAllString(e, ""))
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
+
+// parseLines parses a map of line numbers to the expected lines, line numbers start at 1
+func parseLines(value interface{}) (interface{}, error) {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map, got %v", value)
+	}
+
+	lines := make(map[int]string)
+	for k, v := range values {
+		n, err := lineNumber(k)
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("line numbers start at 1, got %d", n)
+		}
+		lines[n] = toString(v)
+	}
+	return lines, nil
+}
+
+// lineNumber converts a key of lines to a line number, json and toml suites only have text keys
+func lineNumber(key interface{}) (int, error) {
+	switch k := key.(type) {
+	case int:
+		return k, nil
+	case string:
+		if n, err := strconv.Atoi(k); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("expected a line number, got %v", key)
+}
+
+func parseTable(value interface{}) (interface{}, error) {
+	table := TableExpectation{}
+	if err := Decode(value, &table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// parseChecksum parses a hex encoded checksum with the given size in bytes
+func parseChecksum(size int) ParseFunc {
+	return func(value interface{}) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a hex encoded checksum, got %v", value)
+		}
+
+		s = strings.TrimSpace(s)
+		if b, err := hex.DecodeString(s); err != nil || len(b) != size {
+			return nil, fmt.Errorf("expected a hex encoded checksum with %d characters, got %s", size*2, s)
+		}
+		return strings.ToLower(s), nil
+	}
+}
+
+// Convert variable to string and remove trailing blank lines
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strings.Trim(s, "\n")
+	}
+	return strings.Trim(fmt.Sprint(value), "\n")
+}
@@ -0,0 +1,181 @@
+package matcher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type prefixMatcher struct{}
+
+func (m prefixMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	return MatcherResult{Success: strings.HasPrefix(got.(string), expected.(string))}
+}
+
+func Test_RegisterAssertion(t *testing.T) {
+	t.Cleanup(RegisterAssertion(Assertion{Key: "test-starts-with", Parse: ParseString, Matcher: prefixMatcher{}}))
+
+	a, ok := GetAssertion("test-starts-with")
+	assert.True(t, ok)
+	assert.Equal(t, "test-starts-with", a.Key)
+	assert.True(t, a.Matcher.Match("hello world", "hello").Success)
+
+	all := Assertions()
+	assert.Equal(t, "test-starts-with", all[len(all)-1].Key)
+}
+
+func Test_RegisterAssertion_Duplicate(t *testing.T) {
+	assert.PanicsWithValue(t, "Assertion 'contains' is already registered", func() {
+		RegisterAssertion(Assertion{Key: "contains", Parse: ParseString, Matcher: prefixMatcher{}})
+	})
+}
+
+func Test_Register(t *testing.T) {
+	t.Cleanup(Register("test-prefix", func() Matcher { return prefixMatcher{} }))
+	assert.IsType(t, prefixMatcher{}, NewMatcher("test-prefix"))
+
+	assert.PanicsWithValue(t, "Matcher 'test-prefix' is already registered", func() {
+		Register("test-prefix", func() Matcher { return prefixMatcher{} })
+	})
+}
+
+func Test_RegisterAssertion_Unregister(t *testing.T) {
+	unregister := RegisterAssertion(Assertion{Key: "test-removed", Parse: ParseString, Matcher: prefixMatcher{}})
+	count := len(Assertions())
+
+	unregister()
+
+	_, ok := GetAssertion("test-removed")
+	assert.False(t, ok)
+	assert.Len(t, Assertions(), count-1)
+}
+
+func Test_GetAssertion_NotExists(t *testing.T) {
+	_, ok := GetAssertion("not-exists")
+	assert.False(t, ok)
+}
+
+func Test_Assertions_Order(t *testing.T) {
+	var keys []string
+	for _, a := range Assertions() {
+		keys = append(keys, a.Key)
+	}
+	assert.Equal(t, []string{"exactly", "contains", "line-count", "lines", "not-contains", "json", "xml", "file", "table"}, keys[:9])
+}
+
+func Test_ParseString(t *testing.T) {
+	got, err := ParseString("hello\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", got)
+
+	got, err = ParseString(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", got)
+
+	_, err = ParseString([]interface{}{"a"})
+	assert.EqualError(t, err, "expected a string, got [a]")
+}
+
+func Test_ParseRawString(t *testing.T) {
+	got, err := ParseRawString("  hello\r\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "  hello\r\n", got)
+
+	_, err = ParseRawString(map[interface{}]interface{}{})
+	assert.NotNil(t, err)
+}
+
+func Test_ParseStringList(t *testing.T) {
+	got, err := ParseStringList([]interface{}{"a", 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "1"}, got)
+
+	got, err = ParseStringList("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, got)
+}
+
+func Test_ParseInt(t *testing.T) {
+	got, err := ParseInt(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, got)
+
+	_, err = ParseInt("two")
+	assert.EqualError(t, err, "expected an int, got two")
+}
+
+func Test_ParseStringMap(t *testing.T) {
+	got, err := ParseStringMap(map[interface{}]interface{}{".key": "value"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{".key": "value"}, got)
+
+	_, err = ParseStringMap("value")
+	assert.EqualError(t, err, "expected a map, got value")
+}
+
+func Test_Decode(t *testing.T) {
+	var table TableExpectation
+	err := Decode(map[interface{}]interface{}{"format": "csv", "row-count": 2}, &table)
+	assert.Nil(t, err)
+	assert.Equal(t, "csv", table.Format)
+	assert.Equal(t, 2, *table.RowCount)
+
+	err = Decode(map[interface{}]interface{}{"unknown": 1}, &table)
+	assert.Equal(t, UnknownKeyError{Key: "unknown"}, err)
+	assert.EqualError(t, err, "unknown key 'unknown'")
+
+	err = Decode(map[interface{}]interface{}{"row-count": "many"}, &table)
+	assert.EqualError(t, err, "cannot unmarshal !!str `many` into int")
+}
+
+func Test_parseLines(t *testing.T) {
+	got, err := parseLines(map[interface{}]interface{}{1: "first", 2: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]string{1: "first", 2: "2"}, got)
+
+	got, err = parseLines(map[interface{}]interface{}{"1": "first", "10": "tenth"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]string{1: "first", 10: "tenth"}, got)
+
+	_, err = parseLines(map[interface{}]interface{}{"one": "first"})
+	assert.EqualError(t, err, "expected a line number, got one")
+
+	_, err = parseLines(map[interface{}]interface{}{0: "first"})
+	assert.EqualError(t, err, "line numbers start at 1, got 0")
+
+	_, err = parseLines(map[interface{}]interface{}{-1: "first"})
+	assert.EqualError(t, err, "line numbers start at 1, got -1")
+}
+
+func Test_LineCountMatcher(t *testing.T) {
+	assert.True(t, LineCountMatcher{}.Match("a\nb", 2).Success)
+	assert.True(t, LineCountMatcher{}.Match("", 0).Success)
+	assert.False(t, LineCountMatcher{}.Match("a", 2).Success)
+}
+
+func Test_LinesMatcher(t *testing.T) {
+	assert.True(t, LinesMatcher{}.Match("a\nb", map[int]string{2: "b"}).Success)
+
+	got := LinesMatcher{}.Match("a\nb", map[int]string{3: "c"})
+	assert.False(t, got.Success)
+	assert.Equal(t, "Line number 3 does not exists in result: \n\na\nb", got.Diff)
+
+	got = LinesMatcher{}.Match("a", map[int]string{0: "a"})
+	assert.False(t, got.Success)
+	assert.Equal(t, "Invalid line number 0, line numbers start at 1", got.Diff)
+}
+
+func Test_EachMatcher(t *testing.T) {
+	m := EachMatcher{ContainsMatcher{}}
+	assert.True(t, m.Match("hello world", []string{"hello", "world"}).Success)
+	assert.False(t, m.Match("hello world", []string{"hello", "moon"}).Success)
+}
+
+func Test_EachMatcher_JoinsAllDiffs(t *testing.T) {
+	got := EachMatcher{ContainsMatcher{}}.Match("hello", []string{"moon", "hello", "sun"})
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "to contain\n\nmoon")
+	assert.Contains(t, got.Diff, "to contain\n\nsun")
+	assert.NotContains(t, got.Diff, "to contain\n\nhello")
+}
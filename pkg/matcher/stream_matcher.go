@@ -0,0 +1,199 @@
+package matcher
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OpenFunc opens the complete output of a command
+type OpenFunc func() (io.ReadCloser, error)
+
+// StreamMatcher is implemented by matchers which can match the complete output of a command
+// if it was truncated because it exceeded the capture limit.
+// got holds the truncated output which is used for the diff.
+type StreamMatcher interface {
+	MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult
+}
+
+var (
+	_ StreamMatcher = (*ContainsMatcher)(nil)
+	_ StreamMatcher = (*NotContainsMatcher)(nil)
+	_ StreamMatcher = (*EachMatcher)(nil)
+	_ StreamMatcher = (*LineCountMatcher)(nil)
+	_ StreamMatcher = (*ByteSizeMatcher)(nil)
+	_ StreamMatcher = (*ChecksumMatcher)(nil)
+)
+
+const streamChunkSize = 64 * 1024
+
+// MatchStream searches the complete output for the expected text, or counts its occurrences
+func (m ContainsMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	if c, ok := expected.(ContainsCount); ok {
+		return streamCount(open, c)
+	}
+
+	found, err := streamContains(open, expected.(string))
+	if err != nil {
+		return streamError(err)
+	}
+
+	result := m.Match(got, expected)
+	result.Success = found
+	return result
+}
+
+// MatchStream searches the complete output for the expected text
+func (m NotContainsMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	found, err := streamContains(open, expected.(string))
+	if err != nil {
+		return streamError(err)
+	}
+
+	result := m.Match(got, expected)
+	result.Success = !found
+	return result
+}
+
+// MatchStream matches each element with the wrapped matcher, if it supports streams
+func (m EachMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	sm, ok := m.Matcher.(StreamMatcher)
+	if !ok {
+		return m.Match(got, expected)
+	}
+
+	var diffs []string
+	for _, e := range toList(expected) {
+		if result := sm.MatchStream(open, got, e); !result.Success {
+			diffs = append(diffs, result.Diff)
+		}
+	}
+
+	if len(diffs) > 0 {
+		return MatcherResult{Success: false, Diff: strings.Join(diffs, "\n")}
+	}
+	return MatcherResult{Success: true}
+}
+
+// MatchStream counts the lines of the complete output, a trailing line break does not start a new line
+func (m LineCountMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	count := 0
+	// an empty output has no lines
+	last := byte('\n')
+	err := readChunks(open, func(chunk []byte) {
+		count += bytes.Count(chunk, []byte(lineBreak))
+		last = chunk[len(chunk)-1]
+	})
+	if err != nil {
+		return streamError(err)
+	}
+
+	if last != '\n' {
+		count++
+	}
+	return EqualMatcher{}.Match(count, expected)
+}
+
+// MatchStream compares the size of the complete output
+func (m ByteSizeMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	size := 0
+	err := readChunks(open, func(chunk []byte) {
+		size += len(chunk)
+	})
+	if err != nil {
+		return streamError(err)
+	}
+
+	return matchSize(size, expected.(int))
+}
+
+// MatchStream calculates the checksum of the complete output
+func (m ChecksumMatcher) MatchStream(open OpenFunc, got string, expected interface{}) MatcherResult {
+	h := newHash(m.Algorithm)
+	err := readChunks(open, func(chunk []byte) {
+		h.Write(chunk)
+	})
+	if err != nil {
+		return streamError(err)
+	}
+
+	return matchChecksum(m.Algorithm, hex.EncodeToString(h.Sum(nil)), expected.(string))
+}
+
+// streamContains searches the text in the output, chunks overlap by the length of the text
+func streamContains(open OpenFunc, text string) (bool, error) {
+	r, err := open()
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	needle := []byte(text)
+	window := make([]byte, 0, streamChunkSize+len(needle))
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := r.Read(buf)
+		window = append(window, buf[:n]...)
+		if bytes.Contains(window, needle) {
+			return true, nil
+		}
+
+		// keep the end of the window in case the text is split between two chunks
+		if keep := len(needle) - 1; len(window) > keep {
+			window = append(window[:0], window[len(window)-keep:]...)
+		}
+
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// streamCount counts the occurrences of the text in the complete output
+func streamCount(open OpenFunc, expected ContainsCount) MatcherResult {
+	r, err := open()
+	if err != nil {
+		return streamError(err)
+	}
+	defer r.Close()
+
+	occ, err := scanOccurrences(r, expected.Text)
+	if err != nil {
+		return streamError(err)
+	}
+	return countResult(occ, expected, "")
+}
+
+func readChunks(open OpenFunc, fn func(chunk []byte)) error {
+	r, err := open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			fn(buf[:n])
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func streamError(err error) MatcherResult {
+	return MatcherResult{
+		Success: false,
+		Diff:    fmt.Sprintf("Could not read the complete output: %s", err),
+	}
+}
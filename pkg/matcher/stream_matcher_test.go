@@ -0,0 +1,69 @@
+package matcher
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openString(s string) OpenFunc {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+func Test_streamContains_SplitBetweenChunks(t *testing.T) {
+	output := strings.Repeat("a", streamChunkSize-3) + "needle" + strings.Repeat("b", streamChunkSize)
+
+	found, err := streamContains(openString(output), "needle")
+	assert.Nil(t, err)
+	assert.True(t, found)
+
+	found, err = streamContains(openString(output), "haystack")
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func TestContainsMatcher_MatchStream(t *testing.T) {
+	got := ContainsMatcher{}.MatchStream(openString("head middle tail"), "head ... tail", "middle")
+	assert.True(t, got.Success)
+
+	got = ContainsMatcher{}.MatchStream(openString("head middle tail"), "head ... tail", "missing")
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "head ... tail")
+}
+
+func TestNotContainsMatcher_MatchStream(t *testing.T) {
+	got := NotContainsMatcher{}.MatchStream(openString("head middle tail"), "head ... tail", "middle")
+	assert.False(t, got.Success)
+
+	got = NotContainsMatcher{}.MatchStream(openString("head middle tail"), "head ... tail", "missing")
+	assert.True(t, got.Success)
+}
+
+func TestEachMatcher_MatchStream(t *testing.T) {
+	got := EachMatcher{ContainsMatcher{}}.MatchStream(openString("head middle tail"), "head ... tail", []string{"middle", "missing"})
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "missing")
+	assert.NotContains(t, got.Diff, "to contain\n\nmiddle")
+}
+
+func TestLineCountMatcher_MatchStream(t *testing.T) {
+	assert.True(t, LineCountMatcher{}.MatchStream(openString("a\nb\nc"), "", 3).Success)
+	assert.True(t, LineCountMatcher{}.MatchStream(openString("a\nb\nc\n"), "", 3).Success)
+	assert.True(t, LineCountMatcher{}.MatchStream(openString(""), "", 0).Success)
+	assert.False(t, LineCountMatcher{}.MatchStream(openString("a"), "", 2).Success)
+}
+
+func TestByteSizeMatcher_MatchStream(t *testing.T) {
+	assert.True(t, ByteSizeMatcher{}.MatchStream(openString("hello"), "", 5).Success)
+	assert.False(t, ByteSizeMatcher{}.MatchStream(openString("hello"), "", 4).Success)
+}
+
+func TestChecksumMatcher_MatchStream(t *testing.T) {
+	m := ChecksumMatcher{Algorithm: ChecksumMD5}
+	assert.True(t, m.MatchStream(openString("hello"), "", "5d41402abc4b2a76b9719d911017c592").Success)
+	assert.False(t, m.MatchStream(openString("hello!"), "", "5d41402abc4b2a76b9719d911017c592").Success)
+}
@@ -0,0 +1,313 @@
+package matcher
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Supported table formats, an empty format detects the format from the header row
+const (
+	TableFormatCSV        = "csv"
+	TableFormatTSV        = "tsv"
+	TableFormatWhitespace = "whitespace"
+)
+
+var (
+	// columns of whitespace aligned tables are separated by at least two spaces or a tab,
+	// if the header does not contain such a separator single spaces are used
+	wideColumnSeparator   = regexp.MustCompile(`\t+| {2,}`)
+	narrowColumnSeparator = regexp.MustCompile(`\s+`)
+	// border lines like "-----" or "+---+---+" are ignored
+	tableBorderLine = regexp.MustCompile(`^[\s\-=+|]+$`)
+)
+
+// TableExpectation represents the assertions on tabular output
+type TableExpectation struct {
+	Format   string              `yaml:"format,omitempty"`
+	RowCount *int                `yaml:"row-count,omitempty"`
+	Cells    []TableCell         `yaml:"cells,omitempty"`
+	Rows     []map[string]string `yaml:"rows,omitempty"`
+}
+
+// TableCell asserts the value of a single cell, rows start counting at 1
+type TableCell struct {
+	Row    int    `yaml:"row"`
+	Column string `yaml:"column"`
+	Value  string `yaml:"value"`
+}
+
+// ParsedTable is the parsed representation of tabular output
+type ParsedTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+// TableMatcher matches tabular output like csv, tsv or whitespace aligned tables
+type TableMatcher struct{}
+
+// Match parses the got value as a table and validates all expectations
+func (m TableMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	exp := expected.(TableExpectation)
+
+	table, err := ParseTable(got.(string), exp.Format)
+	if err != nil {
+		return MatcherResult{
+			Success: false,
+			Diff:    fmt.Sprintf("Could not parse table: %s\n\n%s", err, got),
+		}
+	}
+
+	if exp.RowCount != nil && len(table.Rows) != *exp.RowCount {
+		return table.failure(fmt.Sprintf("Expected table to have %d rows, got %d", *exp.RowCount, len(table.Rows)))
+	}
+
+	for _, c := range exp.Cells {
+		col := table.columnIndex(c.Column)
+		if col < 0 {
+			return table.failure(fmt.Sprintf(`Column "%s" does not exist`, c.Column))
+		}
+
+		if c.Row <= 0 || c.Row > len(table.Rows) {
+			return table.failure(fmt.Sprintf("Row %d does not exist", c.Row))
+		}
+
+		if v := table.Rows[c.Row-1][col]; v != c.Value {
+			return table.failure(fmt.Sprintf(`Expected cell in row %d and column "%s" with value
+
+%s
+
+to be equal to
+
+%s`, c.Row, c.Column, v, c.Value))
+		}
+	}
+
+	for _, r := range exp.Rows {
+		for col := range r {
+			if table.columnIndex(col) < 0 {
+				return table.failure(fmt.Sprintf(`Column "%s" does not exist`, col))
+			}
+		}
+
+		if !table.hasRow(r) {
+			return table.failure(fmt.Sprintf("Expected table to contain a row with\n\n%s", formatRow(r)))
+		}
+	}
+
+	return MatcherResult{Success: true}
+}
+
+// ParseTable parses the given text into a ParsedTable. The first non-empty line is used as the header row.
+func ParseTable(text string, format string) (ParsedTable, error) {
+	lines := nonEmptyLines(text)
+	if len(lines) == 0 {
+		return ParsedTable{}, fmt.Errorf("no header row found")
+	}
+
+	if format == "" {
+		format = detectTableFormat(lines[0])
+	}
+
+	switch format {
+	case TableFormatCSV:
+		return parseSeparatedTable(text, ',')
+	case TableFormatTSV:
+		return parseSeparatedTable(text, '\t')
+	case TableFormatWhitespace:
+		return parseAlignedTable(lines), nil
+	default:
+		return ParsedTable{}, fmt.Errorf("unknown table format '%s'", format)
+	}
+}
+
+func detectTableFormat(header string) string {
+	switch {
+	case strings.Contains(header, "\t"):
+		return TableFormatTSV
+	case strings.Contains(header, ","):
+		return TableFormatCSV
+	default:
+		return TableFormatWhitespace
+	}
+}
+
+// parseSeparatedTable parses csv and tsv tables, blank lines are skipped unless they are part of a quoted cell
+func parseSeparatedTable(text string, separator rune) (ParsedTable, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = separator
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.LazyQuotes = true
+
+	all, err := r.ReadAll()
+	if err != nil {
+		return ParsedTable{}, err
+	}
+
+	// empty lines are skipped by the reader, lines which only contain whitespace are read as a single blank cell
+	var records [][]string
+	for _, record := range all {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return ParsedTable{}, fmt.Errorf("no header row found")
+	}
+
+	table := ParsedTable{Header: trimAll(records[0])}
+	for _, record := range records[1:] {
+		table.Rows = append(table.Rows, table.normalizeRow(trimAll(record)))
+	}
+
+	return table, nil
+}
+
+// parseAlignedTable parses tables which are aligned by whitespace, like the output of ps or docker ps.
+// Cells are cut at the start positions of the header columns if a row can not be split
+// into the same amount of columns as the header.
+func parseAlignedTable(lines []string) ParsedTable {
+	separator := narrowColumnSeparator
+	if wideColumnSeparator.MatchString(strings.TrimSpace(lines[0])) {
+		separator = wideColumnSeparator
+	}
+
+	var starts []int
+	var table ParsedTable
+	for _, loc := range fieldLocations(lines[0], separator) {
+		starts = append(starts, len([]rune(lines[0][:loc[0]])))
+		table.Header = append(table.Header, lines[0][loc[0]:loc[1]])
+	}
+
+	for _, line := range lines[1:] {
+		if tableBorderLine.MatchString(line) {
+			continue
+		}
+
+		fields := separator.Split(strings.TrimSpace(line), -1)
+		if len(fields) == len(table.Header) {
+			table.Rows = append(table.Rows, fields)
+			continue
+		}
+
+		table.Rows = append(table.Rows, table.normalizeRow(cutColumns([]rune(line), starts)))
+	}
+
+	return table
+}
+
+// fieldLocations returns the start and end index of all fields which are divided by the separator
+func fieldLocations(line string, separator *regexp.Regexp) [][]int {
+	offset := len(line) - len(strings.TrimLeft(line, " \t"))
+	end := len(strings.TrimRight(line, " \t"))
+
+	var locations [][]int
+	start := offset
+	for _, sep := range separator.FindAllStringIndex(line[offset:end], -1) {
+		locations = append(locations, []int{start, offset + sep[0]})
+		start = offset + sep[1]
+	}
+
+	return append(locations, []int{start, end})
+}
+
+func cutColumns(line []rune, starts []int) []string {
+	cells := make([]string, len(starts))
+	for i, s := range starts {
+		if s >= len(line) {
+			break
+		}
+
+		end := len(line)
+		if i+1 < len(starts) && starts[i+1] < end {
+			end = starts[i+1]
+		}
+		cells[i] = strings.TrimSpace(string(line[s:end]))
+	}
+	return cells
+}
+
+// normalizeRow pads or cuts the row to the length of the header
+func (t ParsedTable) normalizeRow(row []string) []string {
+	normalized := make([]string, len(t.Header))
+	copy(normalized, row)
+	return normalized
+}
+
+func (t ParsedTable) columnIndex(name string) int {
+	for i, h := range t.Header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t ParsedTable) hasRow(expected map[string]string) bool {
+	for _, row := range t.Rows {
+		matched := true
+		for col, value := range expected {
+			if row[t.columnIndex(col)] != value {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (t ParsedTable) failure(message string) MatcherResult {
+	return MatcherResult{
+		Success: false,
+		Diff:    fmt.Sprintf("%s\n\nParsed table:\n\n%s", message, t.String()),
+	}
+}
+
+// String renders the table aligned by whitespace
+func (t ParsedTable) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.Header, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func formatRow(row map[string]string) string {
+	var cols []string
+	for h, v := range row {
+		cols = append(cols, fmt.Sprintf("%s: %s", h, v))
+	}
+	sort.Strings(cols)
+	return strings.Join(cols, "\n")
+}
+
+func nonEmptyLines(text string) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func trimAll(values []string) []string {
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
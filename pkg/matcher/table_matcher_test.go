@@ -0,0 +1,131 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const alignedTable = `NAME        STATUS     RESTARTS
+web-1       Running    0
+db-1        Pending    3
+`
+
+func Test_NewMatcher_Table(t *testing.T) {
+	got := NewMatcher(Table)
+	assert.IsType(t, TableMatcher{}, got)
+}
+
+func TestParseTable_Whitespace(t *testing.T) {
+	table, err := ParseTable(alignedTable, "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NAME", "STATUS", "RESTARTS"}, table.Header)
+	assert.Equal(t, [][]string{{"web-1", "Running", "0"}, {"db-1", "Pending", "3"}}, table.Rows)
+}
+
+func TestParseTable_WhitespaceWithEmptyCells(t *testing.T) {
+	text := `CONTAINER ID   IMAGE    PORTS
+abc123         alpine
+def456         nginx    80/tcp`
+
+	table, err := ParseTable(text, TableFormatWhitespace)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CONTAINER ID", "IMAGE", "PORTS"}, table.Header)
+	assert.Equal(t, [][]string{{"abc123", "alpine", ""}, {"def456", "nginx", "80/tcp"}}, table.Rows)
+}
+
+func TestParseTable_CSV(t *testing.T) {
+	table, err := ParseTable("name,status\nweb,\"Running, healthy\"\n", "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name", "status"}, table.Header)
+	assert.Equal(t, [][]string{{"web", "Running, healthy"}}, table.Rows)
+}
+
+func TestParseTable_CSVWithBlankLines(t *testing.T) {
+	text := "\nname,notes\r\n  \nweb,\"first\n\nsecond\"\n\ndb,none\n"
+	table, err := ParseTable(text, "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name", "notes"}, table.Header)
+	assert.Equal(t, [][]string{{"web", "first\n\nsecond"}, {"db", "none"}}, table.Rows)
+
+	got := TableMatcher{}.Match(text, TableExpectation{Cells: []TableCell{{Row: 1, Column: "notes", Value: "first\n\nsecond"}}})
+	assert.True(t, got.Success, got.Diff)
+}
+
+func TestParseTable_TSV(t *testing.T) {
+	table, err := ParseTable("name\tstatus\nweb\tRunning", "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"web", "Running"}}, table.Rows)
+}
+
+func TestParseTable_UnknownFormat(t *testing.T) {
+	_, err := ParseTable(alignedTable, "yaml")
+	assert.Equal(t, "unknown table format 'yaml'", err.Error())
+}
+
+func TestTableMatcher_Match(t *testing.T) {
+	rows := 2
+	m := TableMatcher{}
+	got := m.Match(alignedTable, TableExpectation{
+		RowCount: &rows,
+		Cells:    []TableCell{{Row: 2, Column: "STATUS", Value: "Pending"}},
+		Rows:     []map[string]string{{"NAME": "web-1", "STATUS": "Running"}},
+	})
+
+	assert.True(t, got.Success)
+	assert.Equal(t, "", got.Diff)
+}
+
+func TestTableMatcher_RowCountFails(t *testing.T) {
+	rows := 0
+	m := TableMatcher{}
+	got := m.Match(alignedTable, TableExpectation{RowCount: &rows})
+
+	diff := `Expected table to have 0 rows, got 2
+
+Parsed table:
+
+NAME   STATUS   RESTARTS
+web-1  Running  0
+db-1   Pending  3`
+	assert.False(t, got.Success)
+	assert.Equal(t, diff, got.Diff)
+}
+
+func TestTableMatcher_CellFails(t *testing.T) {
+	m := TableMatcher{}
+
+	got := m.Match(alignedTable, TableExpectation{Cells: []TableCell{{Row: 1, Column: "STATUS", Value: "Pending"}}})
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, `Expected cell in row 1 and column "STATUS" with value`)
+
+	got = m.Match(alignedTable, TableExpectation{Cells: []TableCell{{Row: 3, Column: "STATUS", Value: "Pending"}}})
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "Row 3 does not exist")
+
+	got = m.Match(alignedTable, TableExpectation{Cells: []TableCell{{Row: 1, Column: "AGE", Value: "1d"}}})
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, `Column "AGE" does not exist`)
+}
+
+func TestTableMatcher_RowFails(t *testing.T) {
+	m := TableMatcher{}
+	got := m.Match(alignedTable, TableExpectation{Rows: []map[string]string{{"NAME": "web-1", "STATUS": "Pending"}}})
+
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "Expected table to contain a row with\n\nNAME: web-1\nSTATUS: Pending")
+	assert.Contains(t, got.Diff, "Parsed table:")
+}
+
+func TestTableMatcher_EmptyOutput(t *testing.T) {
+	m := TableMatcher{}
+	got := m.Match("", TableExpectation{})
+
+	assert.False(t, got.Success)
+	assert.Contains(t, got.Diff, "Could not parse table: no header row found")
+}
@@ -0,0 +1,150 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Annotations of transcript lines which are matched as patterns
+const (
+	RegexAnnotation = " (re)"
+	GlobAnnotation  = " (glob)"
+)
+
+// TranscriptMatcher matches the output line by line against the expected lines.
+// Lines ending with " (re)" are regular expressions and lines ending with " (glob)" are glob patterns
+// where * matches any text and ? a single character, both have to match the complete line.
+// With Raw the output is matched exactly, only the final line break is removed, see TranscriptLines.
+type TranscriptMatcher struct {
+	Raw bool
+}
+
+// Match matches the output, the diff shows the lines which did not match
+func (m TranscriptMatcher) Match(got interface{}, expected interface{}) MatcherResult {
+	lines := splitTranscript(got.(string))
+	if m.Raw {
+		lines = TranscriptLines(got.(string))
+	}
+	patterns := expected.([]string)
+
+	matched := len(lines) == len(patterns)
+	// annotated lines which match are displayed with the output to only show the differences
+	display := make([]string, len(patterns))
+	for i, p := range patterns {
+		display[i] = p
+		if i < len(lines) && MatchTranscriptLine(p, lines[i]) {
+			display[i] = lines[i]
+			continue
+		}
+		matched = false
+	}
+
+	if matched {
+		return MatcherResult{Success: true}
+	}
+
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.Join(lines, lineBreak)),
+		B:        difflib.SplitLines(strings.Join(display, lineBreak)),
+		FromFile: "Got",
+		ToFile:   "Expected",
+		Context:  3,
+	}
+	diffText, _ := difflib.GetUnifiedDiffString(diff)
+
+	return MatcherResult{
+		Success: false,
+		Diff:    diffText,
+	}
+}
+
+// MatchTranscriptLine matches a line against an expected line of a transcript, see TranscriptMatcher
+func MatchTranscriptLine(expected string, line string) bool {
+	re, err := transcriptPattern(expected)
+	if err != nil {
+		return false
+	}
+	if re == nil {
+		return expected == line
+	}
+	return re.MatchString(line)
+}
+
+// transcriptPattern compiles the pattern of an annotated line, lines without an annotation return nil
+func transcriptPattern(line string) (*regexp.Regexp, error) {
+	switch {
+	case strings.HasSuffix(line, RegexAnnotation):
+		return regexp.Compile("^(?:" + strings.TrimSuffix(line, RegexAnnotation) + ")$")
+	case strings.HasSuffix(line, GlobAnnotation):
+		return regexp.Compile("^" + globToRegex(strings.TrimSuffix(line, GlobAnnotation)) + "$")
+	}
+	return nil, nil
+}
+
+// globToRegex converts a glob pattern, * and ? can be escaped with a backslash
+func globToRegex(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '\\' && i+1 < len(glob) && (glob[i+1] == '*' || glob[i+1] == '?' || glob[i+1] == '\\'):
+			b.WriteString(regexp.QuoteMeta(glob[i+1 : i+2]))
+			i++
+		case c == '*':
+			b.WriteString(".*")
+		case c == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// TranscriptLines splits the raw output of a command into the lines of a transcript,
+// windows line breaks are converted and only the final line break is removed
+func TranscriptLines(output string) []string {
+	if output == "" {
+		return []string{}
+	}
+	output = strings.ReplaceAll(output, "\r\n", lineBreak)
+	return strings.Split(strings.TrimSuffix(output, lineBreak), lineBreak)
+}
+
+func splitTranscript(output string) []string {
+	if output == "" {
+		return []string{}
+	}
+	return strings.Split(output, lineBreak)
+}
+
+// parseTranscript parses a list of lines or a text with multiple lines, the patterns of annotated lines are validated
+func parseTranscript(value interface{}) (interface{}, error) {
+	var lines []string
+	switch v := value.(type) {
+	case []interface{}:
+		lines = []string{}
+		for _, l := range v {
+			if _, ok := l.(map[interface{}]interface{}); ok {
+				return nil, fmt.Errorf("expected a line, got %v", l)
+			}
+			if _, ok := l.([]interface{}); ok {
+				return nil, fmt.Errorf("expected a line, got %v", l)
+			}
+			lines = append(lines, fmt.Sprint(l))
+		}
+	case map[interface{}]interface{}:
+		return nil, fmt.Errorf("expected a list of lines, got %v", value)
+	default:
+		lines = splitTranscript(toString(value))
+	}
+
+	for _, l := range lines {
+		if _, err := transcriptPattern(l); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %s", l, err)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,70 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscriptMatcher_Match(t *testing.T) {
+	m := TranscriptMatcher{}
+
+	assert.True(t, m.Match("hello\nversion 1.2.3\nfile.txt", []string{"hello", `version \d+\.\d+\.\d+ (re)`, "*.txt (glob)"}).Success)
+	assert.True(t, m.Match("", []string{}).Success)
+	assert.True(t, m.Match("a*b?", []string{`a\*b\? (glob)`}).Success)
+
+	assert.False(t, m.Match("hello", []string{}).Success)
+	assert.False(t, m.Match("", []string{"hello"}).Success)
+	assert.False(t, m.Match("hello world", []string{"hello (re)"}).Success)
+	assert.False(t, m.Match("axb", []string{`a\*b (glob)`}).Success)
+}
+
+func TestTranscriptMatcher_Raw(t *testing.T) {
+	m := TranscriptMatcher{Raw: true}
+
+	assert.True(t, m.Match("  a\n\nb\n", []string{"  a", "", "b"}).Success)
+	assert.True(t, m.Match("a\r\nb\r\n", []string{"a", "b"}).Success)
+	assert.True(t, m.Match("\n", []string{""}).Success)
+	assert.True(t, m.Match("", []string{}).Success)
+
+	assert.False(t, m.Match("  a\n", []string{"a"}).Success)
+	assert.False(t, m.Match("a\n\n", []string{"a"}).Success)
+}
+
+func TestTranscriptMatcher_Diff(t *testing.T) {
+	got := "version 1.2.3\nhello\nbye"
+	expected := []string{`version \d+ (re)`, "hello", "* (glob)", "missing"}
+
+	r := TranscriptMatcher{}.Match(got, expected)
+
+	assert.False(t, r.Success)
+	assert.Equal(t, `--- Got
++++ Expected
+@@ -1,3 +1,4 @@
+-version 1.2.3
++version \d+ (re)
+ hello
+ bye
++missing
+`, r.Diff)
+}
+
+func TestParseTranscript(t *testing.T) {
+	lines, err := parseTranscript([]interface{}{"hello", 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hello", "1"}, lines)
+
+	lines, err = parseTranscript("hello\nworld\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hello", "world"}, lines)
+
+	lines, err = parseTranscript([]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, lines)
+
+	_, err = parseTranscript([]interface{}{"(unclosed (re)"})
+	assert.Equal(t, "invalid pattern '(unclosed (re)': error parsing regexp: missing closing ): `^(?:(unclosed)$`", err.Error())
+
+	_, err = parseTranscript(map[interface{}]interface{}{"a": "b"})
+	assert.NotNil(t, err)
+}
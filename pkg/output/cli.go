@@ -1,6 +1,7 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -39,13 +40,38 @@ func NewCliOutput(color bool) OutputWriter {
 type TestResult struct {
 	FileName       string
 	Title          string
+	Description    string
 	Node           string
 	Tries          int
 	Success        bool
 	FailedProperty string
 	Diff           string
+	Failures       []runtime.Failure
 	Error          error
 	Skipped        bool
+	SkipReason     string
+	Tags           []string
+}
+
+// WithWriter returns a copy of the OutputWriter which writes to out, i.e. to buffer the output of a suite which is executed concurrently
+func (w OutputWriter) WithWriter(out io.Writer) OutputWriter {
+	w.out = out
+	return w
+}
+
+// Flush writes the buffered output to the output
+func (w *OutputWriter) Flush(buf *bytes.Buffer) {
+	if _, err := buf.WriteTo(w.out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// FileResult is the result of a suite file which was executed in directory mode,
+// Error is set if the suite could not be loaded
+type FileResult struct {
+	FileName string
+	Result   runtime.Result
+	Error    error
 }
 
 // GetEventHandler create a new runtime.EventHandler
@@ -70,16 +96,64 @@ func (w *OutputWriter) PrintSummary(result runtime.Result) bool {
 		w.printFailures(result.TestResults)
 	}
 
+	w.printTotal(result, result.Failed == 0)
+	return result.Failed == 0
+}
+
+// PrintDirSummary prints the summary of a directory with the results of each file,
+// the directory failed if a test failed or a file could not be loaded
+func (w *OutputWriter) PrintDirSummary(result runtime.Result, files []FileResult) bool {
+	success := result.Failed == 0
+	for _, f := range files {
+		if f.Error != nil {
+			success = false
+		}
+	}
+
+	if !success {
+		w.printFailures(result.TestResults)
+		w.printFileErrors(files)
+	}
+
+	w.fprintf("")
+	w.fprintf(w.au.Bold("Files"))
+	w.fprintf("")
+	for _, f := range files {
+		if f.Error != nil || f.Result.Failed > 0 {
+			w.fprintf(w.au.Red(w.template.fileSummary(f)))
+			continue
+		}
+		w.fprintf(w.template.fileSummary(f))
+	}
+
+	w.printTotal(result, success)
+	return success
+}
+
+func (w *OutputWriter) printTotal(result runtime.Result, success bool) {
 	w.fprintf("")
 	w.fprintf(w.template.duration(result))
 	summary := w.template.summary(result)
-	if result.Failed > 0 {
-		w.fprintf(w.au.Red(summary))
-	} else {
+	if success {
 		w.fprintf(w.au.Green(summary))
+	} else {
+		w.fprintf(w.au.Red(summary))
 	}
+}
 
-	return result.Failed == 0
+// PrintUpdated prints that the expected output of the transcript was updated
+func (w *OutputWriter) PrintUpdated(file string) {
+	w.fprintf(w.au.Yellow(fmt.Sprintf("Updated transcript %s", file)))
+}
+
+func (w *OutputWriter) printFileErrors(files []FileResult) {
+	for _, f := range files {
+		if f.Error == nil {
+			continue
+		}
+		w.fprintf(w.au.Bold(w.au.Red(w.template.fileError(f))))
+		w.fprintf(f.Error.Error())
+	}
 }
 
 // printResult prints the simple output form of a TestReault
@@ -92,7 +166,11 @@ func (w *OutputWriter) printResult(r TestResult) {
 }
 
 func (w *OutputWriter) printSkip(r TestResult) {
-	w.fprintf(fmt.Sprintf("- [%s] %s, was skipped", r.Node, r.Title))
+	reason := ""
+	if r.SkipReason != "" {
+		reason = fmt.Sprintf(" (%s)", r.SkipReason)
+	}
+	w.fprintf(fmt.Sprintf("- [%s] %s, was skipped%s%s", r.Node, r.Title, reason, w.template.tags(r)))
 }
 
 func (w *OutputWriter) printFailures(results []runtime.TestResult) {
@@ -108,17 +186,43 @@ func (w *OutputWriter) printFailures(results []runtime.TestResult) {
 
 		if r.Error != nil {
 			w.fprintf(w.au.Bold(w.au.Red(w.template.errors(r))))
+			w.printMessages(r.Description, "")
 			w.fprintf(r.Error.Error())
 			continue
 		}
 
 		if !r.Success {
-			w.fprintf(w.au.Bold(w.au.Red(w.template.failures(r))))
-			w.fprintf(r.Diff)
+			w.printTestFailures(r)
 		}
 	}
 }
 
+// printTestFailures prints every failed assertion of the test result
+func (w *OutputWriter) printTestFailures(r TestResult) {
+	failures := r.Failures
+	if len(failures) == 0 {
+		failures = []runtime.Failure{{Property: r.FailedProperty, Diff: r.Diff}}
+	}
+
+	for _, f := range failures {
+		r.FailedProperty = f.Property
+		r.Diff = f.Diff
+		w.fprintf(w.au.Bold(w.au.Red(w.template.failures(r))))
+		w.printMessages(r.Description, f.Message)
+		w.fprintf(r.Diff)
+	}
+}
+
+// printMessages prints the description of the test and the message of the failed assertion if they are set
+func (w *OutputWriter) printMessages(description string, message string) {
+	if description != "" {
+		w.fprintf("Description: " + description)
+	}
+	if message != "" {
+		w.fprintf("Message: " + message)
+	}
+}
+
 func (w *OutputWriter) fprintf(a ...interface{}) {
 	if _, err := fmt.Fprintln(w.out, a...); err != nil {
 		log.Fatal(err)
@@ -130,13 +234,17 @@ func convertTestResult(tr runtime.TestResult) TestResult {
 	testResult := TestResult{
 		FileName:       tr.TestCase.FileName,
 		Title:          tr.TestCase.Title,
+		Description:    tr.TestCase.Description,
 		Node:           tr.Node,
 		Tries:          tr.Tries,
 		Success:        tr.ValidationResult.Success,
 		FailedProperty: tr.FailedProperty,
 		Diff:           tr.ValidationResult.Diff,
+		Failures:       tr.Failures,
 		Error:          tr.TestCase.Result.Error,
 		Skipped:        tr.Skipped,
+		SkipReason:     tr.SkipReason,
+		Tags:           tr.TestCase.Tags,
 	}
 
 	return testResult
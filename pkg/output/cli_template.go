@@ -27,6 +27,11 @@ var resultTmpl = `
 	{{if gt .Tries 1 }}, retries {{.Tries }}{{- end}}
 {{- end -}}
 
+// Add Tags Template
+{{define "tags" -}}
+	{{range .Tags}} #{{.}}{{- end}}
+{{- end -}}
+
 // BaseResult
 {{define "baseResult" -}}
 	{{template "mark" .}}{{template "file" .}} [{{ .Node }}]
@@ -43,14 +48,28 @@ var commanderTmpl = `
 	Count: {{len .TestResults}}, Failed: {{ .Failed }}, Skipped: {{ .Skipped }}
 {{- end -}}
 
+// File summary
+{{define "fileSummary" -}}
+	{{if .Error -}}
+		✗ [{{ .FileName }}] could not be loaded
+	{{- else -}}
+		{{if .Result.Failed}}✗{{else}}✓{{end}} [{{ .FileName }}] Count: {{len .Result.TestResults}}, Failed: {{ .Result.Failed }}, Skipped: {{ .Result.Skipped }}
+	{{- end}}
+{{- end -}}
+
+// File error
+{{define "fileError" -}}
+	✗ [{{ .FileName }}] could not be loaded with error message:
+{{- end -}}
+
 // Result
 {{define "result" -}}
-	{{template "baseResult" .}} {{ .Title }}{{template "tries" .}}
+	{{template "baseResult" .}} {{ .Title }}{{template "tries" .}}{{template "tags" .}}
 {{- end -}}
 
 // Failure
 {{- define "failure" -}}
-	{{- template "baseResult" .}} '{{ .Title }}', on property '{{ .FailedProperty }}'
+	{{- template "baseResult" .}} '{{ .Title }}', on property '{{ .FailedProperty }}'{{template "tags" .}}
 {{- end -}}
 
 // Error
@@ -83,11 +102,26 @@ func (t cliTemplate) summary(result runtime.Result) string {
 	return tpl.String()
 }
 
+func (t cliTemplate) fileSummary(file FileResult) string {
+	tpl := t.getTemplatedString("fileSummary", file)
+	return tpl.String()
+}
+
+func (t cliTemplate) fileError(file FileResult) string {
+	tpl := t.getTemplatedString("fileError", file)
+	return tpl.String()
+}
+
 func (t cliTemplate) testResult(testResult TestResult) string {
 	tpl := t.getTemplatedString("result", testResult)
 	return tpl.String()
 }
 
+func (t cliTemplate) tags(testResult TestResult) string {
+	tpl := t.getTemplatedString("tags", testResult)
+	return tpl.String()
+}
+
 func (t cliTemplate) failures(testResult TestResult) string {
 	tpl := t.getTemplatedString("failure", testResult)
 	return tpl.String()
@@ -54,6 +54,37 @@ func Test_EventHandlerTestSkipped(t *testing.T) {
 	assert.Contains(t, output, "- [192.168.0.1] Skipped test, was skipped")
 }
 
+func Test_EventHandler_PrintsTags(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+	eh := writer.GetEventHandler()
+
+	tc := runtime.TestCase{Title: "tagged", Tags: []string{"smoke", "slow"}}
+	eh.TestFinished(runtime.TestResult{TestCase: tc, Node: "local", ValidationResult: runtime.ValidationResult{Success: true}})
+	eh.TestSkipped(runtime.TestResult{TestCase: tc, Node: "local", Skipped: true})
+
+	output := buf.String()
+	assert.Contains(t, output, "✓ [local] tagged #smoke #slow\n")
+	assert.Contains(t, output, "- [local] tagged, was skipped #smoke #slow\n")
+}
+
+func Test_EventHandler_PrintsSkipReason(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+	eh := writer.GetEventHandler()
+
+	eh.TestSkipped(runtime.TestResult{
+		TestCase:   runtime.TestCase{Title: "apt --version", Tags: []string{"linux"}},
+		Node:       "local",
+		Skipped:    true,
+		SkipReason: "run-if: apt is not in PATH",
+	})
+
+	assert.Equal(t, "- [local] apt --version, was skipped (run-if: apt is not in PATH) #linux\n", buf.String())
+}
+
 func Test_PrintSummary(t *testing.T) {
 	r := runtime.Result{
 		Duration:    10,
@@ -73,6 +104,117 @@ func Test_PrintSummary(t *testing.T) {
 	assert.NotContains(t, output, "✓ [docker-host] Successful test")
 }
 
+func Test_PrintSummary_PrintsAllFailures(t *testing.T) {
+	r := runtime.Result{
+		Failed: 1,
+		TestResults: []runtime.TestResult{{
+			TestCase:         runtime.TestCase{Title: "Failed test"},
+			ValidationResult: runtime.ValidationResult{Success: false, Diff: "stdout diff"},
+			FailedProperty:   runtime.Stdout,
+			Failures: []runtime.Failure{
+				{Property: runtime.Stdout, Diff: "stdout diff"},
+				{Property: runtime.ExitCode, Diff: "exit code diff"},
+			},
+			Node: "local",
+		}},
+	}
+
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+
+	writer.PrintSummary(r)
+
+	output := buf.String()
+	assert.Contains(t, output, "✗ [local] 'Failed test', on property 'Stdout'\nstdout diff")
+	assert.Contains(t, output, "✗ [local] 'Failed test', on property 'ExitCode'\nexit code diff")
+}
+
+func Test_PrintSummary_PrintsDescriptionAndMessages(t *testing.T) {
+	r := runtime.Result{
+		Failed: 1,
+		TestResults: []runtime.TestResult{{
+			TestCase: runtime.TestCase{
+				Title:       "Failed test",
+				Description: "guards the release banner",
+			},
+			Failures: []runtime.Failure{
+				{Property: runtime.Stdout, Diff: "stdout diff", Message: "banner is missing"},
+				{Property: runtime.ExitCode, Diff: "exit code diff"},
+			},
+			Node: "local",
+		}},
+	}
+
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+
+	writer.PrintSummary(r)
+
+	output := buf.String()
+	assert.Contains(t, output, "✗ [local] 'Failed test', on property 'Stdout'\nDescription: guards the release banner\nMessage: banner is missing\nstdout diff")
+	assert.Contains(t, output, "✗ [local] 'Failed test', on property 'ExitCode'\nDescription: guards the release banner\nexit code diff")
+}
+
+func Test_PrintDirSummary(t *testing.T) {
+	passed := runtime.Result{
+		Skipped:     1,
+		TestResults: []runtime.TestResult{{Skipped: true}, {ValidationResult: runtime.ValidationResult{Success: true}}},
+	}
+	failed := runtime.Result{Failed: 1, TestResults: createFakeTestResults()}
+
+	files := []FileResult{
+		{FileName: "a.yaml", Result: passed},
+		{FileName: "b.yaml", Result: failed},
+		{FileName: "c.yaml", Error: fmt.Errorf("yaml: line 1: did not find expected node content")},
+	}
+	r := runtime.Result{
+		Failed:      1,
+		Skipped:     1,
+		TestResults: append(passed.TestResults, failed.TestResults...),
+	}
+
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+
+	assert.False(t, writer.PrintDirSummary(r, files))
+
+	output := buf.String()
+	assert.Contains(t, output, "✗ [192.168.0.1] 'Failed test', on property 'Stdout'")
+	assert.Contains(t, output, "✗ [c.yaml] could not be loaded with error message:\nyaml: line 1: did not find expected node content")
+	assert.Contains(t, output, "Files\n\n✓ [a.yaml] Count: 2, Failed: 0, Skipped: 1\n✗ [b.yaml] Count: 4, Failed: 1, Skipped: 0\n✗ [c.yaml] could not be loaded\n")
+	assert.Contains(t, output, "Count: 6, Failed: 1, Skipped: 1")
+}
+
+func Test_PrintDirSummary_FileErrorFailsWithoutFailedTests(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &buf
+
+	assert.False(t, writer.PrintDirSummary(runtime.Result{}, []FileResult{{FileName: "a.yaml", Error: fmt.Errorf("invalid")}}))
+	assert.True(t, writer.PrintDirSummary(runtime.Result{}, []FileResult{{FileName: "a.yaml"}}))
+}
+
+func Test_WithWriter_Flush(t *testing.T) {
+	var out, buf bytes.Buffer
+	writer := NewCliOutput(false)
+	writer.out = &out
+
+	buffered := writer.WithWriter(&buf)
+	buffered.GetEventHandler().TestFinished(runtime.TestResult{
+		TestCase:         runtime.TestCase{Title: "buffered", FileName: "a.yaml"},
+		ValidationResult: runtime.ValidationResult{Success: true},
+		Node:             "local",
+	})
+	assert.Empty(t, out.String())
+
+	writer.Flush(&buf)
+	assert.Equal(t, "✓ [a.yaml] [local] buffered\n", out.String())
+	assert.Zero(t, buf.Len())
+}
+
 func createFakeTestResults() []runtime.TestResult {
 	tr := runtime.TestResult{
 		TestCase: runtime.TestCase{
@@ -0,0 +1,356 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   int
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes like 512, 64KB or 10MB, an empty size means unlimited
+func parseByteSize(size string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	if s == "" {
+		return 0, nil
+	}
+
+	unit := 1
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid max-output '%s', expected a size like 512, 64KB or 10MB", size)
+	}
+	return n * unit, nil
+}
+
+// ValidateMaxOutput validates the size of the max-output config
+func ValidateMaxOutput(size string) error {
+	_, err := parseByteSize(size)
+	return err
+}
+
+// outputCapture captures an output stream up to a limit, a limit of 0 captures the complete output in memory.
+// If the limit is exceeded only the head and the tail of the output are kept in memory
+// and the complete output is streamed to a temporary file.
+// It is safe to be used by concurrent writers, i.e. for the combined output.
+type outputCapture struct {
+	mu    sync.Mutex
+	limit int
+	size  int64
+	// buf holds the complete output until the limit is exceeded, afterwards only the head
+	buf  []byte
+	tail ringBuffer
+	file *os.File
+}
+
+func newOutputCapture(limit int) *outputCapture {
+	return &outputCapture{limit: limit}
+}
+
+func (c *outputCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		if c.limit <= 0 || len(c.buf)+len(p) <= c.limit {
+			c.buf = append(c.buf, p...)
+			c.size += int64(len(p))
+			return len(p), nil
+		}
+
+		if err := c.spill(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	n, err := c.file.Write(p)
+	c.size += int64(n)
+	c.tail.Write(p[:n])
+	return n, err
+}
+
+// spill moves the captured output and p to a temporary file and keeps the head and tail in memory
+func (c *outputCapture) spill(p []byte) error {
+	f, err := os.CreateTemp("", "commander-output-*")
+	if err != nil {
+		return err
+	}
+
+	data := append(c.buf, p...)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	head := c.limit / 2
+	c.file = f
+	c.size += int64(len(p))
+	c.tail = newRingBuffer(c.limit - head)
+	c.tail.Write(data[head:])
+	c.buf = data[:head]
+	return nil
+}
+
+// Truncated returns true if the output exceeded the limit
+func (c *outputCapture) Truncated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file != nil
+}
+
+// String returns the captured output, truncated output contains a marker with the amount of omitted bytes
+func (c *outputCapture) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return string(c.buf)
+	}
+
+	tail := c.tail.Bytes()
+	omitted := c.size - int64(len(c.buf)) - int64(len(tail))
+	return fmt.Sprintf("%s\n\n... %d bytes truncated ...\n\n%s", c.buf, omitted, tail)
+}
+
+// Open opens the complete output, it is only available if the output was truncated
+func (c *outputCapture) Open() (io.ReadCloser, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil, fmt.Errorf("output was not truncated")
+	}
+	return os.Open(c.file.Name())
+}
+
+// Close removes the temporary file
+func (c *outputCapture) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file != nil {
+		c.file.Close()
+		os.Remove(c.file.Name())
+	}
+}
+
+// opener returns the function to open the complete output, or nil if the output is kept in memory completely
+func (c *outputCapture) opener() matcher.OpenFunc {
+	if c == nil || !c.Truncated() {
+		return nil
+	}
+	return c.Open
+}
+
+// ringBuffer keeps the last written bytes up to its size
+type ringBuffer struct {
+	buf  []byte
+	pos  int
+	full bool
+}
+
+func newRingBuffer(size int) ringBuffer {
+	return ringBuffer{buf: make([]byte, size)}
+}
+
+func (r *ringBuffer) Write(p []byte) {
+	size := len(r.buf)
+	if size == 0 {
+		return
+	}
+
+	if len(p) >= size {
+		copy(r.buf, p[len(p)-size:])
+		r.pos = 0
+		r.full = true
+		return
+	}
+
+	n := copy(r.buf[r.pos:], p)
+	copy(r.buf, p[n:])
+	if r.pos+len(p) >= size {
+		r.full = true
+	}
+	r.pos = (r.pos + len(p)) % size
+}
+
+// Bytes returns the kept bytes in the order they were written
+func (r *ringBuffer) Bytes() []byte {
+	if !r.full {
+		return append([]byte{}, r.buf[:r.pos]...)
+	}
+	return append(append([]byte{}, r.buf[r.pos:]...), r.buf[:r.pos]...)
+}
+
+// outputCaptures holds the captures of stdout, stderr and the combined output of a command
+type outputCaptures struct {
+	stdout   *outputCapture
+	stderr   *outputCapture
+	combined *outputCapture
+	// raw keeps the complete output as it was produced, see processOutput
+	raw bool
+}
+
+// newOutputCaptures creates the captures with the max-output limit of the test
+func newOutputCaptures(test TestCase) (*outputCaptures, error) {
+	limit, err := parseByteSize(test.Command.MaxOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outputCaptures{
+		stdout:   newOutputCapture(limit),
+		stderr:   newOutputCapture(limit),
+		combined: newOutputCapture(limit),
+		raw:      test.Command.RawOutput,
+	}, nil
+}
+
+// StdoutWriter writes to the stdout and the combined capture
+func (c *outputCaptures) StdoutWriter() io.Writer {
+	return io.MultiWriter(c.stdout, c.combined)
+}
+
+// StderrWriter writes to the stderr and the combined capture
+func (c *outputCaptures) StderrWriter() io.Writer {
+	return io.MultiWriter(c.stderr, c.combined)
+}
+
+// Result creates the CommandResult of the captured output, the captures must not be closed before the result was validated
+func (c *outputCaptures) Result(test TestCase, exitCode int) CommandResult {
+	return CommandResult{
+		ExitCode: exitCode,
+		Stdout:   processOutput(c.stdout.String(), test.Command.RawOutput),
+		Stderr:   processOutput(c.stderr.String(), test.Command.RawOutput),
+		Output:   processOutput(c.combined.String(), test.Command.RawOutput),
+		captures: c,
+	}
+}
+
+// openers returns the functions to open the complete stdout, stderr and combined output,
+// they are nil if the output was not truncated.
+// The output is processed like the output in memory, see processOutput.
+func (r CommandResult) openers() (matcher.OpenFunc, matcher.OpenFunc, matcher.OpenFunc) {
+	if r.captures == nil {
+		return nil, nil, nil
+	}
+
+	c := r.captures
+	return c.processed(c.stdout.opener()), c.processed(c.stderr.opener()), c.processed(c.combined.opener())
+}
+
+// truncated returns true if stdout or stderr exceeded the max-output limit
+func (r CommandResult) truncated() bool {
+	return r.captures != nil && (r.captures.stdout.Truncated() || r.captures.stderr.Truncated())
+}
+
+func (c *outputCaptures) processed(open matcher.OpenFunc) matcher.OpenFunc {
+	if open == nil || c.raw {
+		return open
+	}
+	return func() (io.ReadCloser, error) {
+		r, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return &processedReader{r: bufio.NewReader(r), closer: r}, nil
+	}
+}
+
+// processedReader removes surrounding whitespace and converts windows line breaks while the output is read,
+// it is the streaming variant of processOutput for ASCII whitespace
+type processedReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	// started is set after the first non whitespace byte, whitespace before it is removed
+	started bool
+	// pending holds whitespace which is only written if another non whitespace byte follows
+	pending []byte
+	ready   []byte
+	err     error
+}
+
+func (p *processedReader) Read(buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		if len(p.ready) > 0 {
+			c := copy(buf[n:], p.ready)
+			p.ready = p.ready[c:]
+			n += c
+			continue
+		}
+		if p.err != nil {
+			break
+		}
+
+		b, err := p.r.ReadByte()
+		if err != nil {
+			p.err = err
+			continue
+		}
+		if b == '\r' {
+			if next, err := p.r.Peek(1); err == nil && next[0] == '\n' {
+				continue
+			}
+		}
+
+		if isASCIISpace(b) {
+			if p.started {
+				p.pending = append(p.pending, b)
+			}
+			continue
+		}
+
+		p.started = true
+		p.ready = append(append(p.ready[:0], p.pending...), b)
+		p.pending = p.pending[:0]
+	}
+
+	if n == 0 && p.err != nil {
+		return 0, p.err
+	}
+	return n, nil
+}
+
+func (p *processedReader) Close() error {
+	return p.closer.Close()
+}
+
+func isASCIISpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// Close removes all temporary files
+func (c *outputCaptures) Close() {
+	c.stdout.Close()
+	c.stderr.Close()
+	c.combined.Close()
+}
@@ -0,0 +1,166 @@
+package runtime
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseByteSize(t *testing.T) {
+	tests := map[string]int{
+		"":      0,
+		"512":   512,
+		"512B":  512,
+		"64KB":  64 * 1024,
+		"10 MB": 10 * 1024 * 1024,
+		"1gb":   1024 * 1024 * 1024,
+	}
+
+	for in, expected := range tests {
+		got, err := parseByteSize(in)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, got, in)
+	}
+
+	_, err := parseByteSize("10 apples")
+	assert.EqualError(t, err, "invalid max-output '10 apples', expected a size like 512, 64KB or 10MB")
+}
+
+func Test_outputCapture_WithinLimit(t *testing.T) {
+	c := newOutputCapture(10)
+	defer c.Close()
+
+	_, _ = c.Write([]byte("hello"))
+
+	assert.False(t, c.Truncated())
+	assert.Equal(t, "hello", c.String())
+	assert.Nil(t, c.opener())
+}
+
+func Test_outputCapture_Unlimited(t *testing.T) {
+	c := newOutputCapture(0)
+	defer c.Close()
+
+	_, _ = c.Write([]byte(strings.Repeat("a", 1000)))
+
+	assert.False(t, c.Truncated())
+	assert.Len(t, c.String(), 1000)
+}
+
+func Test_outputCapture_ExceedsLimit(t *testing.T) {
+	c := newOutputCapture(8)
+
+	_, _ = c.Write([]byte("0123"))
+	_, _ = c.Write([]byte("456789"))
+	_, _ = c.Write([]byte("abcdef"))
+
+	assert.True(t, c.Truncated())
+	assert.Equal(t, "0123\n\n... 8 bytes truncated ...\n\ncdef", c.String())
+
+	r, err := c.opener()()
+	assert.Nil(t, err)
+	content, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "0123456789abcdef", string(content))
+
+	name := c.file.Name()
+	c.Close()
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_ringBuffer(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]byte("ab"))
+	assert.Equal(t, "ab", string(r.Bytes()))
+
+	r.Write([]byte("cde"))
+	assert.Equal(t, "bcde", string(r.Bytes()))
+
+	r.Write([]byte("f"))
+	assert.Equal(t, "cdef", string(r.Bytes()))
+
+	r.Write([]byte("0123456789"))
+	assert.Equal(t, "6789", string(r.Bytes()))
+}
+
+func Test_ValidateTruncatedOutput(t *testing.T) {
+	captures, _ := newOutputCaptures(TestCase{Command: CommandUnderTest{MaxOutput: "64B"}})
+	defer captures.Close()
+
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, strings.Repeat("x", i%10)+"line")
+	}
+	lines[50] = "needle"
+	_, _ = captures.StdoutWriter().Write([]byte(strings.Join(lines, "\n") + "\n"))
+
+	test := TestCase{
+		Expected: Expected{
+			Stdout: ExpectedOut{
+				Contains:    []string{"needle"},
+				LineCount:   100,
+				NotContains: []string{"haystack"},
+			},
+		},
+		Result: captures.Result(TestCase{}, 0),
+	}
+
+	assert.Contains(t, test.Result.Stdout, "bytes truncated")
+	assert.NotContains(t, test.Result.Stdout, "needle")
+
+	got := Validate(test)
+	assert.True(t, got.ValidationResult.Success, got.ValidationResult.Diff)
+
+	test.Expected.Stdout = ExpectedOut{NotContains: []string{"needle"}}
+	got = Validate(test)
+	assert.False(t, got.ValidationResult.Success)
+	assert.Contains(t, got.ValidationResult.Diff, "bytes truncated")
+
+	test.Expected.Stdout = ExpectedOut{Lines: map[int]string{100: "line"}}
+	got = Validate(test)
+	assert.False(t, got.ValidationResult.Success)
+	assert.Contains(t, got.ValidationResult.Diff, "output was truncated by max-output, lines needs the complete output")
+
+	test.Expected.Stdout = ExpectedOut{Contains: []string{"needle"}, Normalize: []Normalizer{{Name: Lowercase}}}
+	got = Validate(test)
+	assert.False(t, got.ValidationResult.Success)
+	assert.Contains(t, got.ValidationResult.Diff, "output was truncated by max-output, contains needs the complete output")
+}
+
+func Test_processedReader(t *testing.T) {
+	for _, output := range []string{"", " \r\n ", "  a\r\nb\r\n\r\n", "a\rb\r", "\n\na  b\t\n\n", strings.Repeat("x \r\n", 1000)} {
+		r := &processedReader{r: bufio.NewReader(iotest.HalfReader(strings.NewReader(output))), closer: io.NopCloser(nil)}
+		got, err := io.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, processOutput(output, false), string(got), "%q", output)
+	}
+}
+
+func Test_ValidateTruncatedOutput_MatchesOutputInMemory(t *testing.T) {
+	output := "\r\n" + strings.Repeat("line\r\n", 100) + "needle\r\nline\r\n\r\n\r\n"
+	expected := ExpectedOut{
+		Contains:    []string{"needle\nline"},
+		LineCount:   102,
+		NotContains: []string{"\r"},
+	}
+
+	for _, maxOutput := range []string{"", "64B"} {
+		captures, _ := newOutputCaptures(TestCase{Command: CommandUnderTest{MaxOutput: maxOutput}})
+		_, _ = captures.StdoutWriter().Write([]byte(output))
+
+		test := TestCase{
+			Expected: Expected{Stdout: expected},
+			Result:   captures.Result(TestCase{}, 0),
+		}
+
+		got := Validate(test)
+		assert.True(t, got.ValidationResult.Success, "max-output %q: %s", maxOutput, got.ValidationResult.Diff)
+		captures.Close()
+	}
+}
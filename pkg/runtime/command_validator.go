@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Constants for the environment variables which are passed to validate commands
+const (
+	ValidatorStdoutEnv   = "COMMANDER_STDOUT"
+	ValidatorStderrEnv   = "COMMANDER_STDERR"
+	ValidatorExitCodeEnv = "COMMANDER_EXIT_CODE"
+	// ValidatorTruncatedEnv is set to 1 if stdout or stderr exceeded max-output,
+	// the passed output only contains its head and tail then
+	ValidatorTruncatedEnv = "COMMANDER_OUTPUT_TRUNCATED"
+)
+
+// maxValidatorEnvSize is the maximum size of the output which is passed in an environment variable,
+// larger values exceed the limits of the operating system and are only available on stdin
+const maxValidatorEnvSize = 32 * 1024
+
+// validateWithCommands executes all validate commands of the test with the given executor,
+// which means they run on the same node as the command under test.
+// The captured stdout is passed to stdin, stdout, stderr and the exit code are passed as environment variables
+// which are not expanded. Outputs larger than maxValidatorEnvSize are not passed as environment variables,
+// truncated outputs are marked with ValidatorTruncatedEnv.
+// A validator passes if it exits with 0, otherwise its output is used as the diff.
+// The failures of all validators are added to the failures of the given result.
+func validateWithCommands(e Executor, tr TestResult) TestResult {
+	test := tr.TestCase
+	failures := tr.Failures
+
+	for _, v := range test.Expected.Validators {
+		// the output is passed literally, it may contain $ which would be expanded like the env of the config
+		env := map[string]string{ValidatorExitCodeEnv: strconv.Itoa(test.Result.ExitCode)}
+		if len(test.Result.Stdout) <= maxValidatorEnvSize {
+			env[ValidatorStdoutEnv] = test.Result.Stdout
+		}
+		if len(test.Result.Stderr) <= maxValidatorEnvSize {
+			env[ValidatorStderrEnv] = test.Result.Stderr
+		}
+		if test.Result.truncated() {
+			env[ValidatorTruncatedEnv] = "1"
+		}
+
+		log.Println("title: '"+test.Title+"'", " Validator: ", v)
+		vr := e.Execute(TestCase{
+			Title: test.Title,
+			Command: CommandUnderTest{
+				Cmd:        v,
+				InheritEnv: test.Command.InheritEnv,
+				Env:        test.Command.Env,
+				LiteralEnv: env,
+				Dir:        test.Command.Dir,
+				Timeout:    test.Command.Timeout,
+				Stdin:      test.Result.Stdout,
+			},
+		})
+
+		if err := vr.TestCase.Result.Error; err != nil {
+			failures = append(failures, Failure{
+				Property: Validator,
+				Diff:     fmt.Sprintf("Validator '%s' could not be executed with error message:\n\n%s", v, err),
+			})
+			continue
+		}
+
+		if code := vr.TestCase.Result.ExitCode; code != 0 {
+			output := strings.TrimSpace(vr.TestCase.Result.Stdout + "\n" + vr.TestCase.Result.Stderr)
+			failures = append(failures, Failure{
+				Property: Validator,
+				Diff:     fmt.Sprintf("Validator '%s' failed with exit code %d:\n\n%s", v, code, output),
+			})
+		}
+	}
+
+	result := newTestResult(test, failures)
+	result.Node = tr.Node
+	result.Tries = tr.Tries
+	return result
+}
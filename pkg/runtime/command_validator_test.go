@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeExecutor records the executed tests and returns the configured result
+type fakeExecutor struct {
+	executed []TestCase
+	result   CommandResult
+}
+
+func (e *fakeExecutor) Execute(test TestCase) TestResult {
+	e.executed = append(e.executed, test)
+	test.Result = e.result
+	return TestResult{TestCase: test}
+}
+
+func Test_validateWithCommands(t *testing.T) {
+	e := &fakeExecutor{}
+	tr := getValidatorTestResult()
+
+	got := validateWithCommands(e, tr)
+
+	assert.True(t, got.ValidationResult.Success)
+	assert.Len(t, e.executed, 1)
+	assert.Equal(t, "./check.sh", e.executed[0].Command.Cmd)
+	assert.Equal(t, "hello", e.executed[0].Command.Stdin)
+	assert.Equal(t, "/tmp", e.executed[0].Command.Dir)
+	assert.Equal(t, map[string]string{"KEY": "value"}, e.executed[0].Command.Env)
+	assert.Equal(t, map[string]string{
+		ValidatorStdoutEnv:   "hello",
+		ValidatorStderrEnv:   "error",
+		ValidatorExitCodeEnv: "3",
+	}, e.executed[0].Command.LiteralEnv)
+}
+
+func Test_validateWithCommands_LargeOutputIsOnlyPassedToStdin(t *testing.T) {
+	e := &fakeExecutor{}
+	tr := getValidatorTestResult()
+	tr.TestCase.Result.Stdout = strings.Repeat("a", maxValidatorEnvSize+1)
+
+	validateWithCommands(e, tr)
+
+	assert.Equal(t, tr.TestCase.Result.Stdout, e.executed[0].Command.Stdin)
+	assert.NotContains(t, e.executed[0].Command.LiteralEnv, ValidatorStdoutEnv)
+	assert.Equal(t, "error", e.executed[0].Command.LiteralEnv[ValidatorStderrEnv])
+}
+
+func Test_validateWithCommands_MarksTruncatedOutput(t *testing.T) {
+	captures, _ := newOutputCaptures(TestCase{Command: CommandUnderTest{MaxOutput: "8B"}})
+	_, _ = captures.StdoutWriter().Write([]byte("more than eight bytes"))
+	captures.Close()
+
+	e := &fakeExecutor{}
+	tr := getValidatorTestResult()
+	tr.TestCase.Result = captures.Result(TestCase{}, 0)
+
+	validateWithCommands(e, tr)
+
+	assert.Equal(t, "1", e.executed[0].Command.LiteralEnv[ValidatorTruncatedEnv])
+	assert.Contains(t, e.executed[0].Command.Stdin, "bytes truncated")
+}
+
+func Test_validateWithCommands_Fails(t *testing.T) {
+	e := &fakeExecutor{result: CommandResult{ExitCode: 1, Stdout: "expected 3 lines", Stderr: "got 1"}}
+	tr := getValidatorTestResult()
+
+	got := validateWithCommands(e, tr)
+
+	assert.False(t, got.ValidationResult.Success)
+	assert.Equal(t, Validator, got.FailedProperty)
+	assert.Equal(t, "Validator './check.sh' failed with exit code 1:\n\nexpected 3 lines\ngot 1", got.ValidationResult.Diff)
+}
+
+func Test_validateWithCommands_CollectsAllFailures(t *testing.T) {
+	e := &fakeExecutor{result: CommandResult{ExitCode: 1}}
+	tr := getValidatorTestResult()
+	tr.TestCase.Expected.Validators = []string{"./check.sh", "./check-again.sh"}
+	tr.ValidationResult = ValidationResult{Success: false, Diff: "stdout diff"}
+	tr.FailedProperty = Stdout
+	tr.Failures = []Failure{{Property: Stdout, Diff: "stdout diff"}}
+
+	got := validateWithCommands(e, tr)
+
+	assert.False(t, got.ValidationResult.Success)
+	assert.Len(t, e.executed, 2)
+	assert.Equal(t, Stdout, got.FailedProperty)
+	assert.Equal(t, "stdout diff", got.ValidationResult.Diff)
+	assert.Len(t, got.Failures, 3)
+	assert.Equal(t, []string{Stdout, Validator}, got.TestCase.Result.FailureProperties)
+}
+
+func getValidatorTestResult() TestResult {
+	return TestResult{
+		TestCase: TestCase{
+			Title: "validated",
+			Command: CommandUnderTest{
+				Cmd: "./run.sh",
+				Dir: "/tmp",
+				Env: map[string]string{"KEY": "value"},
+			},
+			Expected: Expected{Validators: []string{"./check.sh"}},
+			Result:   CommandResult{Stdout: "hello", Stderr: "error", ExitCode: 3},
+		},
+		ValidationResult: ValidationResult{Success: true},
+	}
+}
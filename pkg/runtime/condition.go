@@ -0,0 +1,187 @@
+package runtime
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	goruntime "runtime"
+	"strings"
+)
+
+// Condition decides if a test is executed, it matches if all of its checks match.
+// OS, Arch, Binaries and Commands are checked on the node of the test,
+// Env is checked on the machine which executes commander.
+type Condition struct {
+	// OS and Arch match if they contain the GOOS and GOARCH of the node, i.e. linux or amd64
+	OS   []string
+	Arch []string
+	// Binaries match if all of them are found in the PATH
+	Binaries []string
+	// Env holds NAME to match if the variable is set or NAME=value to match its value
+	Env []string
+	// Commands are probes which match if all of them exit with 0
+	Commands []string
+}
+
+// skipReason checks the run-if and skip-if conditions of the test on the executor,
+// it returns why the test is skipped or an empty string if it is executed
+func skipReason(e Executor, t TestCase) string {
+	if t.RunIf != nil {
+		if ok, reason := t.RunIf.check(e, t); !ok {
+			return "run-if: " + reason
+		}
+	}
+	if t.SkipIf != nil {
+		if ok, reason := t.SkipIf.check(e, t); ok {
+			return "skip-if: " + reason
+		}
+	}
+	return ""
+}
+
+// check returns if the condition matches and the checks which decided it,
+// which is the first check which failed or all checks if the condition matches.
+// Checks are evaluated in order, probes are not executed if another check failed.
+func (c Condition) check(e Executor, t TestCase) (bool, string) {
+	// the local node is checked directly, other nodes with commands on the node
+	_, local := e.(LocalExecutor)
+
+	var checks []func() (bool, string)
+	if len(c.OS) > 0 {
+		checks = append(checks, func() (bool, string) {
+			if local {
+				return matchPlatform("os", goruntime.GOOS, c.OS)
+			}
+			return matchNodePlatform(e, t, "os", "uname -s", c.OS)
+		})
+	}
+	if len(c.Arch) > 0 {
+		checks = append(checks, func() (bool, string) {
+			if local {
+				return matchPlatform("arch", goruntime.GOARCH, c.Arch)
+			}
+			return matchNodePlatform(e, t, "arch", "uname -m", c.Arch)
+		})
+	}
+	for _, b := range c.Binaries {
+		b := b
+		checks = append(checks, func() (bool, string) {
+			if local {
+				return lookPath(b)
+			}
+			return lookNodePath(e, t, b)
+		})
+	}
+	for _, env := range c.Env {
+		env := env
+		checks = append(checks, func() (bool, string) { return matchEnv(env) })
+	}
+	for _, cmd := range c.Commands {
+		cmd := cmd
+		checks = append(checks, func() (bool, string) { return probe(e, t, cmd) })
+	}
+
+	var reasons []string
+	for _, check := range checks {
+		ok, reason := check()
+		if !ok {
+			return false, reason
+		}
+		reasons = append(reasons, reason)
+	}
+	return true, strings.Join(reasons, ", ")
+}
+
+func matchPlatform(name string, actual string, expected []string) (bool, string) {
+	for _, v := range expected {
+		if strings.EqualFold(v, actual) {
+			return true, fmt.Sprintf("%s is %s", name, actual)
+		}
+	}
+	return false, fmt.Sprintf("%s %s is not %s", name, actual, strings.Join(expected, " or "))
+}
+
+// nodePlatforms maps the output of uname to the GOOS and GOARCH names
+var nodePlatforms = map[string]string{
+	"Linux":   "linux",
+	"Darwin":  "darwin",
+	"FreeBSD": "freebsd",
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"aarch64": "arm64",
+	"arm64":   "arm64",
+	"i386":    "386",
+	"i686":    "386",
+	"armv7l":  "arm",
+}
+
+// matchNodePlatform detects the platform of the node with the uname command
+func matchNodePlatform(e Executor, t TestCase, name string, cmd string, expected []string) (bool, string) {
+	r := executeOnNode(e, t, cmd)
+	if r.Error != nil || r.ExitCode != 0 {
+		return false, fmt.Sprintf("%s of the node could not be detected with '%s'", name, cmd)
+	}
+
+	actual := strings.TrimSpace(r.Stdout)
+	if p, ok := nodePlatforms[actual]; ok {
+		actual = p
+	}
+	return matchPlatform(name, actual, expected)
+}
+
+func lookPath(binary string) (bool, string) {
+	if _, err := exec.LookPath(binary); err != nil {
+		return false, fmt.Sprintf("%s is not in PATH", binary)
+	}
+	return true, fmt.Sprintf("%s is in PATH", binary)
+}
+
+// lookNodePath looks up the binary in the PATH of the node
+func lookNodePath(e Executor, t TestCase, binary string) (bool, string) {
+	r := executeOnNode(e, t, "command -v '"+strings.ReplaceAll(binary, "'", `'\''`)+"'")
+	if r.Error != nil || r.ExitCode != 0 {
+		return false, fmt.Sprintf("%s is not in PATH", binary)
+	}
+	return true, fmt.Sprintf("%s is in PATH", binary)
+}
+
+func matchEnv(env string) (bool, string) {
+	name, expected, hasValue := strings.Cut(env, "=")
+	value, set := os.LookupEnv(name)
+	switch {
+	case !hasValue && set:
+		return true, fmt.Sprintf("env %s is set", name)
+	case !hasValue:
+		return false, fmt.Sprintf("env %s is not set", name)
+	case set && value == expected:
+		return true, fmt.Sprintf("env %s is %s", name, expected)
+	default:
+		return false, fmt.Sprintf("env %s is not %s", name, expected)
+	}
+}
+
+// probe executes the command on the executor with the config of the test
+func probe(e Executor, t TestCase, cmd string) (bool, string) {
+	r := executeOnNode(e, t, cmd)
+	if r.Error != nil {
+		return false, fmt.Sprintf("'%s' could not be executed: %s", cmd, r.Error)
+	}
+	return r.ExitCode == 0, fmt.Sprintf("'%s' exited with %d", cmd, r.ExitCode)
+}
+
+// executeOnNode executes a command of a check on the executor with the config of the test
+func executeOnNode(e Executor, t TestCase, cmd string) CommandResult {
+	log.Println("title: '"+t.Title+"'", " Probe: ", cmd)
+	r := e.Execute(TestCase{
+		Title: t.Title,
+		Command: CommandUnderTest{
+			Cmd:        cmd,
+			InheritEnv: t.Command.InheritEnv,
+			Env:        t.Command.Env,
+			Dir:        t.Command.Dir,
+			Timeout:    t.Command.Timeout,
+		},
+	})
+	return r.TestCase.Result
+}
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Condition_Platform(t *testing.T) {
+	ok, reason := Condition{OS: []string{runtime.GOOS}, Arch: []string{"other", runtime.GOARCH}}.check(LocalExecutor{}, TestCase{})
+	assert.True(t, ok)
+	assert.Equal(t, "os is "+runtime.GOOS+", arch is "+runtime.GOARCH, reason)
+
+	ok, reason = Condition{OS: []string{"plan9", "aix"}}.check(LocalExecutor{}, TestCase{})
+	assert.False(t, ok)
+	assert.Equal(t, "os "+runtime.GOOS+" is not plan9 or aix", reason)
+}
+
+func Test_Condition_PlatformOfNode(t *testing.T) {
+	e := &fakeExecutor{result: CommandResult{Stdout: "Linux"}}
+	test := TestCase{Command: CommandUnderTest{Dir: "/tmp"}}
+
+	ok, reason := Condition{OS: []string{"linux"}}.check(e, test)
+	assert.True(t, ok)
+	assert.Equal(t, "os is linux", reason)
+	assert.Equal(t, "uname -s", e.executed[0].Command.Cmd)
+	assert.Equal(t, "/tmp", e.executed[0].Command.Dir)
+
+	e = &fakeExecutor{result: CommandResult{Stdout: "aarch64"}}
+	ok, reason = Condition{Arch: []string{"amd64"}}.check(e, test)
+	assert.False(t, ok)
+	assert.Equal(t, "arch arm64 is not amd64", reason)
+	assert.Equal(t, "uname -m", e.executed[0].Command.Cmd)
+
+	e = &fakeExecutor{result: CommandResult{ExitCode: 127}}
+	ok, reason = Condition{OS: []string{"linux"}}.check(e, test)
+	assert.False(t, ok)
+	assert.Equal(t, "os of the node could not be detected with 'uname -s'", reason)
+}
+
+func Test_Condition_Binaries(t *testing.T) {
+	ok, reason := Condition{Binaries: []string{"go", "commander-does-not-exist"}}.check(LocalExecutor{}, TestCase{})
+
+	assert.False(t, ok)
+	assert.Equal(t, "commander-does-not-exist is not in PATH", reason)
+}
+
+func Test_Condition_BinariesOfNode(t *testing.T) {
+	e := &fakeExecutor{}
+
+	ok, reason := Condition{Binaries: []string{"docker"}}.check(e, TestCase{})
+	assert.True(t, ok)
+	assert.Equal(t, "docker is in PATH", reason)
+	assert.Equal(t, "command -v 'docker'", e.executed[0].Command.Cmd)
+
+	e = &fakeExecutor{result: CommandResult{ExitCode: 1}}
+	ok, reason = Condition{Binaries: []string{"it's"}}.check(e, TestCase{})
+	assert.False(t, ok)
+	assert.Equal(t, "it's is not in PATH", reason)
+	assert.Equal(t, `command -v 'it'\''s'`, e.executed[0].Command.Cmd)
+}
+
+func Test_Condition_Env(t *testing.T) {
+	t.Setenv("COMMANDER_CONDITION", "yes")
+
+	ok, reason := Condition{Env: []string{"COMMANDER_CONDITION", "COMMANDER_CONDITION=yes"}}.check(&fakeExecutor{}, TestCase{})
+	assert.True(t, ok)
+	assert.Equal(t, "env COMMANDER_CONDITION is set, env COMMANDER_CONDITION is yes", reason)
+
+	ok, reason = Condition{Env: []string{"COMMANDER_CONDITION=no"}}.check(&fakeExecutor{}, TestCase{})
+	assert.False(t, ok)
+	assert.Equal(t, "env COMMANDER_CONDITION is not no", reason)
+
+	ok, reason = Condition{Env: []string{"COMMANDER_CONDITION_UNSET"}}.check(&fakeExecutor{}, TestCase{})
+	assert.False(t, ok)
+	assert.Equal(t, "env COMMANDER_CONDITION_UNSET is not set", reason)
+}
+
+func Test_Condition_Commands(t *testing.T) {
+	e := &fakeExecutor{result: CommandResult{ExitCode: 1}}
+	test := TestCase{Command: CommandUnderTest{Dir: "/tmp", Env: map[string]string{"KEY": "value"}}}
+
+	ok, reason := Condition{Commands: []string{"test -f probe", "never executed"}}.check(e, test)
+
+	assert.False(t, ok)
+	assert.Equal(t, "'test -f probe' exited with 1", reason)
+	assert.Len(t, e.executed, 1)
+	assert.Equal(t, "/tmp", e.executed[0].Command.Dir)
+	assert.Equal(t, map[string]string{"KEY": "value"}, e.executed[0].Command.Env)
+}
+
+func Test_Condition_ProbesAreNotExecutedIfAnotherCheckFailed(t *testing.T) {
+	e := &fakeExecutor{}
+
+	ok, _ := Condition{Env: []string{"COMMANDER_CONDITION_UNSET"}, Commands: []string{"probe"}}.check(e, TestCase{})
+
+	assert.False(t, ok)
+	assert.Empty(t, e.executed)
+}
+
+func Test_skipReason(t *testing.T) {
+	e := LocalExecutor{}
+
+	assert.Equal(t, "", skipReason(e, TestCase{}))
+	assert.Equal(t, "", skipReason(e, TestCase{RunIf: &Condition{OS: []string{runtime.GOOS}}, SkipIf: &Condition{OS: []string{"plan9"}}}))
+	assert.Equal(t, "run-if: os "+runtime.GOOS+" is not plan9", skipReason(e, TestCase{RunIf: &Condition{OS: []string{"plan9"}}}))
+	assert.Equal(t, "skip-if: os is "+runtime.GOOS, skipReason(e, TestCase{SkipIf: &Condition{OS: []string{runtime.GOOS}}}))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -33,6 +34,15 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 	log.Printf("DOCKER_CERT_PATH: %s \n", os.Getenv("DOCKER_CERT_PATH"))
 	log.Printf("DOCKER_API_VERSION: %s \n", os.Getenv("DOCKER_API_VERSION"))
 
+	captures, err := newOutputCaptures(test)
+	if err != nil {
+		test.Result = CommandResult{Error: err}
+		return TestResult{
+			TestCase: test,
+		}
+	}
+	defer captures.Close()
+
 	ctx := context.Background()
 	cli, err := client.NewClientWithOpts(client.WithAPIVersionNegotiation(), client.FromEnv)
 	if err != nil {
@@ -67,7 +77,7 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 	log.Printf("Pull log image'%s':\n %s\n", e.Image, buf.String())
 
 	var env []string
-	for k, v := range test.Command.Env {
+	for k, v := range mergeEnv(test.Command.Env, test.Command.LiteralEnv) {
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 
@@ -80,6 +90,8 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 			User:       e.ExecUser,
 			Cmd:        []string{"/bin/sh", "-c", test.Command.Cmd},
 			Tty:        false,
+			OpenStdin:  test.Command.Stdin != "",
+			StdinOnce:  test.Command.Stdin != "",
 		}, nil, nil, nil, "")
 	if err != nil {
 		test.Result.Error = fmt.Errorf("could not pull image '%s' with error: '%s'", e.Image, err)
@@ -88,6 +100,22 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 		}
 	}
 
+	if test.Command.Stdin != "" {
+		hijacked, err := cli.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{Stream: true, Stdin: true})
+		if err != nil {
+			test.Result.Error = fmt.Errorf("could not attach stdin to container '%s' with error: '%s'", resp.ID, err)
+			return TestResult{
+				TestCase: test,
+			}
+		}
+		defer hijacked.Close()
+
+		go func() {
+			_, _ = io.Copy(hijacked.Conn, strings.NewReader(test.Command.Stdin))
+			_ = hijacked.CloseWrite()
+		}()
+	}
+
 	log.Printf("Started container %s %s\n", e.Image, resp.ID)
 	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
 		test.Result.Error = fmt.Errorf("could not pull image '%s' with error: '%s'", e.Image, err)
@@ -115,10 +143,8 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 		panic(err)
 	}
 
-	stdout := &bytes.Buffer{}
-	stderr := &bytes.Buffer{}
-
-	_, err = stdcopy.StdCopy(stdout, stderr, out)
+	// the log stream is multiplexed in chronological order
+	_, err = stdcopy.StdCopy(captures.StdoutWriter(), captures.StderrWriter(), out)
 	if err != nil {
 		panic(err)
 	}
@@ -129,11 +155,7 @@ func (e DockerExecutor) Execute(test TestCase) TestResult {
 
 	// status := <-waitBody
 	// Write test result
-	test.Result = CommandResult{
-		ExitCode: int(status.StatusCode),
-		Stdout:   strings.TrimSpace(strings.ReplaceAll(stdout.String(), "\r\n", "\n")),
-		Stderr:   strings.TrimSpace(strings.ReplaceAll(stderr.String(), "\r\n", "\n")),
-	}
+	test.Result = captures.Result(test, int(status.StatusCode))
 
 	log.Println("title: '"+test.Title+"'", " ExitCode: ", test.Result.ExitCode)
 	log.Println("title: '"+test.Title+"'", " Stdout: ", test.Result.Stdout)
@@ -74,6 +74,29 @@ func Test_DockerExecutor_ExecuteCatchStderr(t *testing.T) {
 	assert.Nil(t, got.TestCase.Result.Error)
 }
 
+func Test_DockerExecutor_Execute_CombinedOutput(t *testing.T) {
+	if !isEnabled() {
+		return
+	}
+
+	d := DockerExecutor{
+		Image: "docker.io/library/ubuntu:18.04",
+	}
+
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd: "echo first; sleep 0.1; echo error >&2; sleep 0.1; echo last",
+		},
+		Expected: Expected{
+			Output: ExpectedOut{Exactly: "first\nerror\nlast"},
+		},
+	}
+
+	got := d.Execute(test)
+	assert.True(t, got.ValidationResult.Success)
+	assert.Equal(t, "first\nerror\nlast", got.TestCase.Result.Output)
+}
+
 func Test_DockerExecutor_Execute_Dir(t *testing.T) {
 	if !isEnabled() {
 		return
@@ -1,6 +1,31 @@
 package runtime
 
+import (
+	"strings"
+)
+
 // Executor interface which will be implemented by all available executors, like ssh or local
 type Executor interface {
 	Execute(test TestCase) TestResult
 }
+
+// processOutput removes surrounding whitespace and converts windows line breaks,
+// raw output is kept as it was produced
+func processOutput(output string, raw bool) string {
+	if raw {
+		return output
+	}
+	return strings.TrimSpace(strings.ReplaceAll(output, "\r\n", "\n"))
+}
+
+// mergeEnv returns the variables of env and additional, additional takes precedence
+func mergeEnv(env map[string]string, additional map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for k, v := range env {
+		merged[k] = v
+	}
+	for k, v := range additional {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+	"gopkg.in/yaml.v2"
+)
+
+// Set assigns the parsed value of the assertion registered with the given key.
+// Built-in assertions are assigned to the field with the same yaml key, all other assertions are stored in Custom.
+func (e *ExpectedOut) Set(key string, value interface{}) {
+	if key == "contains" {
+		e.setContains(value)
+		return
+	}
+
+	index, ok := assertionFields()[key]
+	if !ok {
+		if e.Custom == nil {
+			e.Custom = make(map[string]interface{})
+		}
+		e.Custom[key] = value
+		return
+	}
+
+	field := reflect.ValueOf(e).Elem().FieldByIndex(index)
+	v := reflect.ValueOf(value)
+	if field.Kind() == reflect.Ptr {
+		p := reflect.New(field.Type().Elem())
+		p.Elem().Set(v)
+		v = p
+	}
+	field.Set(v)
+}
+
+// Values returns the expected values of all registered assertions which are set, mapped by their key.
+// An assertion is set if its field is not the zero value, maps are only set if they are not empty.
+func (e ExpectedOut) Values() map[string]interface{} {
+	values := make(map[string]interface{})
+	fields := assertionFields()
+	v := reflect.ValueOf(e)
+	for _, a := range matcher.Assertions() {
+		if a.Key == "contains" {
+			if len(e.ContainsCount) > 0 {
+				values[a.Key] = e.containsList()
+			} else if len(e.Contains) > 0 {
+				values[a.Key] = e.Contains
+			}
+			continue
+		}
+
+		index, ok := fields[a.Key]
+		if !ok {
+			if c, ok := e.Custom[a.Key]; ok {
+				values[a.Key] = c
+			}
+			continue
+		}
+
+		field := v.FieldByIndex(index)
+		if field.IsZero() || (field.Kind() == reflect.Map && field.Len() == 0) {
+			continue
+		}
+		values[a.Key] = reflect.Indirect(field).Interface()
+	}
+	return values
+}
+
+var (
+	assertionFieldsOnce sync.Once
+	assertionFieldIndex map[string][]int
+)
+
+// assertionFields maps the yaml keys of the fields of ExpectedOut to their index,
+// fields which are not assertions are excluded
+func assertionFields() map[string][]int {
+	assertionFieldsOnce.Do(func() {
+		assertionFieldIndex = make(map[string][]int)
+		t := reflect.TypeOf(ExpectedOut{})
+		for i := 0; i < t.NumField(); i++ {
+			key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			switch key {
+			case "", "-", "message", "messages", "normalize":
+				continue
+			}
+			assertionFieldIndex[key] = t.Field(i).Index
+		}
+	})
+	return assertionFieldIndex
+}
+
+// setContains assigns a list of texts, or a list of texts and counted texts
+func (e *ExpectedOut) setContains(value interface{}) {
+	if texts, ok := value.([]string); ok {
+		e.Contains = append(e.Contains, texts...)
+		return
+	}
+
+	for _, v := range value.([]interface{}) {
+		switch c := v.(type) {
+		case matcher.ContainsCount:
+			e.ContainsCount = append(e.ContainsCount, c)
+		default:
+			e.Contains = append(e.Contains, c.(string))
+		}
+	}
+}
+
+// containsList joins the texts and counted texts of contains
+func (e ExpectedOut) containsList() []interface{} {
+	var list []interface{}
+	for _, c := range e.Contains {
+		list = append(list, c)
+	}
+	for _, c := range e.ContainsCount {
+		list = append(list, c)
+	}
+	return list
+}
+
+// MarshalYAML adds the counted texts to the contains list, all other properties are marshalled by their tags
+func (e ExpectedOut) MarshalYAML() (interface{}, error) {
+	type plain ExpectedOut
+	if len(e.ContainsCount) == 0 {
+		return plain(e), nil
+	}
+
+	p := plain(e)
+	p.Contains = nil
+	content, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties yaml.MapSlice
+	if err := yaml.Unmarshal(content, &properties); err != nil {
+		return nil, err
+	}
+	// contains is the first property of the struct
+	return append(yaml.MapSlice{{Key: "contains", Value: e.containsList()}}, properties...), nil
+}
+
+// message returns the custom message of the assertion with the given key, or the message of all assertions
+func (e ExpectedOut) message(key string) string {
+	if m, ok := e.Messages[key]; ok {
+		return m
+	}
+	return e.Message
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+)
+
+func Test_ExpectedOut_BuiltInAssertionsHaveFields(t *testing.T) {
+	for _, a := range matcher.Assertions() {
+		if a.Key == "contains" {
+			continue
+		}
+		assert.Contains(t, assertionFields(), a.Key, "assertion %s has no field in ExpectedOut", a.Key)
+	}
+}
+
+func Test_ExpectedOut_SetAndValues(t *testing.T) {
+	t.Cleanup(matcher.RegisterAssertion(matcher.Assertion{Key: "test-custom", Parse: matcher.ParseString, Matcher: matcher.TextMatcher{}}))
+
+	count := 2
+	table := matcher.TableExpectation{}
+	values := map[string]interface{}{
+		"exactly":     "hello",
+		"contains":    []interface{}{"hello", matcher.ContainsCount{Text: "l", Count: &count}},
+		"lines":       map[int]string{1: "hello"},
+		"table":       table,
+		"byte-size":   5,
+		"transcript":  []string{},
+		"test-custom": "he",
+	}
+
+	e := ExpectedOut{}
+	for k, v := range values {
+		e.Set(k, v)
+	}
+
+	assert.Equal(t, "hello", e.Exactly)
+	assert.Equal(t, []string{"hello"}, e.Contains)
+	assert.Equal(t, []matcher.ContainsCount{{Text: "l", Count: &count}}, e.ContainsCount)
+	assert.Equal(t, &table, e.Table)
+	assert.Equal(t, 5, *e.ByteSize)
+	assert.Equal(t, map[string]interface{}{"test-custom": "he"}, e.Custom)
+	assert.Equal(t, values, e.Values())
+}
+
+func Test_ExpectedOut_ValuesOmitsUnsetAssertions(t *testing.T) {
+	assert.Empty(t, ExpectedOut{Message: "failed", Normalize: []Normalizer{{Name: Lowercase}}, JSON: map[string]string{}}.Values())
+}
@@ -29,6 +29,15 @@ func (e LocalExecutor) Execute(test TestCase) TestResult {
 		}
 	}
 
+	captures, err := newOutputCaptures(test)
+	if err != nil {
+		test.Result = CommandResult{Error: err}
+		return TestResult{
+			TestCase: test,
+		}
+	}
+	defer captures.Close()
+
 	envOpt := createEnvVarsOption(test)
 
 	// cut = command under test
@@ -36,7 +45,9 @@ func (e LocalExecutor) Execute(test TestCase) TestResult {
 		test.Command.Cmd,
 		cmd.WithWorkingDir(test.Command.Dir),
 		timeoutOpt,
-		envOpt)
+		envOpt,
+		createStdinOption(test),
+		createCaptureOption(captures))
 
 	if err := cut.Execute(); err != nil {
 		log.Println(test.Title, " failed ", err.Error())
@@ -54,11 +65,7 @@ func (e LocalExecutor) Execute(test TestCase) TestResult {
 	log.Println("title: '"+test.Title+"'", " Env: ", cut.Env)
 
 	// Write test result
-	test.Result = CommandResult{
-		ExitCode: cut.ExitCode(),
-		Stdout:   strings.TrimSpace(strings.ReplaceAll(cut.Stdout(), "\r\n", "\n")),
-		Stderr:   strings.TrimSpace(strings.ReplaceAll(cut.Stderr(), "\r\n", "\n")),
-	}
+	test.Result = captures.Result(test, cut.ExitCode())
 
 	log.Println("title: '"+test.Title+"'", " ExitCode: ", test.Result.ExitCode)
 	log.Println("title: '"+test.Title+"'", " Stdout: ", test.Result.Stdout)
@@ -81,6 +88,31 @@ func createEnvVarsOption(test TestCase) func(c *cmd.Command) {
 		for k, v := range test.Command.Env {
 			c.AddEnv(k, v)
 		}
+
+		// AddEnv expands variables, literal values are added directly
+		for k, v := range test.Command.LiteralEnv {
+			c.Env = append(c.Env, k+"="+v)
+		}
+	}
+}
+
+func createStdinOption(test TestCase) func(c *cmd.Command) {
+	return func(c *cmd.Command) {
+		if test.Command.Stdin == "" {
+			return
+		}
+
+		base := newBaseCommand()
+		base.Stdin = strings.NewReader(test.Command.Stdin)
+		cmd.WithCustomBaseCommand(base)(c)
+	}
+}
+
+// createCaptureOption writes the output to the captures instead of the unbounded buffers of the command
+func createCaptureOption(captures *outputCaptures) func(c *cmd.Command) {
+	return func(c *cmd.Command) {
+		c.StdoutWriter = captures.StdoutWriter()
+		c.StderrWriter = captures.StderrWriter()
 	}
 }
 
@@ -44,3 +44,32 @@ func TestRuntime_WithInheritFromShell_Overwrite(t *testing.T) {
 
 	assert.Equal(t, "overwrite from-parent", got.TestCase.Result.Stdout)
 }
+
+func TestRuntime_WithStdin(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:   "cat",
+			Stdin: "from stdin",
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.Equal(t, "from stdin", got.TestCase.Result.Stdout)
+}
+
+func TestRuntime_WithCombinedOutput(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd: "echo first; sleep 0.1; echo error >&2; sleep 0.1; echo last",
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.Equal(t, "first\nlast", got.TestCase.Result.Stdout)
+	assert.Equal(t, "error", got.TestCase.Result.Stderr)
+	assert.Equal(t, "first\nerror\nlast", got.TestCase.Result.Output)
+}
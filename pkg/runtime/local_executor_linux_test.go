@@ -3,6 +3,7 @@ package runtime
 import (
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +45,98 @@ func TestRuntime_WithInheritFromShell_Overwrite(t *testing.T) {
 
 	assert.Equal(t, "overwrite from-parent", got.TestCase.Result.Stdout)
 }
+
+func TestRuntime_WithLiteralEnv(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:        `printf '%s' "$LITERAL"`,
+			LiteralEnv: map[string]string{"LITERAL": "cost $HOME ${USER}"},
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.Equal(t, "cost $HOME ${USER}", got.TestCase.Result.Stdout)
+}
+
+func TestRuntime_ValidatorOutputIsNotExpanded(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{Cmd: `echo 'cost $HOME'`},
+		Expected: Expected{
+			Validators: []string{`test "$COMMANDER_STDOUT" = 'cost $HOME'`},
+		},
+	}
+
+	e := LocalExecutor{}
+	got := validateWithCommands(e, e.Execute(test))
+
+	assert.True(t, got.ValidationResult.Success, got.ValidationResult.Diff)
+}
+
+func TestRuntime_WithStdin(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:   "cat",
+			Stdin: "from stdin",
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.Equal(t, "from stdin", got.TestCase.Result.Stdout)
+}
+
+func TestRuntime_WithCombinedOutput(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd: "echo first; sleep 0.1; echo error >&2; sleep 0.1; echo last",
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.Equal(t, "first\nlast", got.TestCase.Result.Stdout)
+	assert.Equal(t, "error", got.TestCase.Result.Stderr)
+	assert.Equal(t, "first\nerror\nlast", got.TestCase.Result.Output)
+}
+
+func TestRuntime_WithMaxOutput(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:       "seq 1 100000",
+			MaxOutput: "1KB",
+		},
+		Expected: Expected{
+			Stdout: ExpectedOut{
+				Contains:  []string{"50000"},
+				LineCount: 100000,
+			},
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.True(t, got.ValidationResult.Success, got.ValidationResult.Diff)
+	assert.Contains(t, got.TestCase.Result.Stdout, "bytes truncated")
+	assert.True(t, strings.HasPrefix(got.TestCase.Result.Stdout, "1\n2\n"))
+	assert.True(t, strings.HasSuffix(got.TestCase.Result.Stdout, "99999\n100000"))
+	assert.Less(t, len(got.TestCase.Result.Stdout), 1100)
+}
+
+func TestRuntime_WithInvalidMaxOutput(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:       "echo hello",
+			MaxOutput: "a lot",
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	assert.EqualError(t, got.TestCase.Result.Error, "invalid max-output 'a lot', expected a size like 512, 64KB or 10MB")
+}
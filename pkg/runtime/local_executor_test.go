@@ -64,3 +64,22 @@ func TestRuntime_WithInvalidDuration(t *testing.T) {
 
 	assert.Equal(t, `time: unknown unit "lightyears" in duration "600lightyears"`, got.TestCase.Result.Error.Error())
 }
+
+func TestRuntime_WithRawOutput(t *testing.T) {
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd:       "echo hello",
+			RawOutput: true,
+		},
+	}
+
+	e := LocalExecutor{}
+	got := e.Execute(test)
+
+	expected := "hello\n"
+	if runtime.GOOS == "windows" {
+		expected = "hello\r\n"
+	}
+	assert.Equal(t, expected, got.TestCase.Result.Stdout)
+	assert.Equal(t, expected, got.TestCase.Result.Output)
+}
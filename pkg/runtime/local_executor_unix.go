@@ -0,0 +1,10 @@
+//go:build !windows
+
+package runtime
+
+import "os/exec"
+
+// newBaseCommand creates the shell which executes the command under test
+func newBaseCommand() *exec.Cmd {
+	return exec.Command("/bin/sh", "-c")
+}
@@ -0,0 +1,8 @@
+package runtime
+
+import "os/exec"
+
+// newBaseCommand creates the shell which executes the command under test
+func newBaseCommand() *exec.Cmd {
+	return exec.Command(`C:\windows\system32\cmd.exe`, "/C")
+}
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Constants for the built-in normalizers
+const (
+	StripANSI          = "strip-ansi"
+	CollapseWhitespace = "collapse-whitespace"
+	Lowercase          = "lowercase"
+	ReplaceTmpDir      = "replace-tmpdir"
+	ReplaceHome        = "replace-home"
+)
+
+var (
+	ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+	whitespace   = regexp.MustCompile(`[ \t]+`)
+)
+
+// Normalizer is a single step of the normalization pipeline which is applied
+// to the output before it is matched.
+// It is either a built-in normalizer referenced by Name or a regex replacement,
+// use NewRegexNormalizer to compile the regex once.
+type Normalizer struct {
+	Name  string
+	Regex string
+	// Replace is inserted literally, $ does not reference groups of the regex
+	Replace string
+	regex   *regexp.Regexp
+}
+
+// NewRegexNormalizer creates a normalizer which replaces all matches of the regex
+func NewRegexNormalizer(regex string, replace string) (Normalizer, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return Normalizer{}, fmt.Errorf("invalid regex in normalize: %s", err)
+	}
+	return Normalizer{Regex: regex, Replace: replace, regex: re}, nil
+}
+
+// Validate checks if the normalizer is a known built-in or a valid regex
+func (n Normalizer) Validate() error {
+	if n.Regex != "" {
+		if _, err := regexp.Compile(n.Regex); err != nil {
+			return fmt.Errorf("invalid regex in normalize: %s", err)
+		}
+		return nil
+	}
+
+	switch n.Name {
+	case StripANSI, CollapseWhitespace, Lowercase, ReplaceTmpDir, ReplaceHome:
+		return nil
+	default:
+		return fmt.Errorf("normalizer %s does not exist", n.Name)
+	}
+}
+
+// Apply applies the normalizer to the given text
+func (n Normalizer) Apply(text string) string {
+	if n.Regex != "" {
+		re := n.regex
+		if re == nil {
+			re = regexp.MustCompile(n.Regex)
+		}
+		return re.ReplaceAllLiteralString(text, n.Replace)
+	}
+
+	switch n.Name {
+	case StripANSI:
+		return ansiSequence.ReplaceAllString(text, "")
+	case CollapseWhitespace:
+		lines := strings.Split(text, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimSpace(whitespace.ReplaceAllString(l, " "))
+		}
+		return strings.Join(lines, "\n")
+	case Lowercase:
+		return strings.ToLower(text)
+	case ReplaceTmpDir:
+		return replacePath(text, os.TempDir(), "$TMPDIR")
+	case ReplaceHome:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return text
+		}
+		return replacePath(text, home, "$HOME")
+	default:
+		panic(fmt.Sprintf("Normalizer '%s' does not exist!", n.Name))
+	}
+}
+
+// MarshalYAML converts built-in normalizers to their name and regex replacements to a map
+func (n Normalizer) MarshalYAML() (interface{}, error) {
+	if n.Regex != "" {
+		return map[string]string{"regex": n.Regex, "replace": n.Replace}, nil
+	}
+	return n.Name, nil
+}
+
+// replacePath replaces the path and its resolved symlink, i.e. /var and /private/var on macOS
+func replacePath(text string, path string, placeholder string) string {
+	path = strings.TrimRight(path, `/\`)
+	if path == "" {
+		return text
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+		text = strings.ReplaceAll(text, resolved, placeholder)
+	}
+
+	return strings.ReplaceAll(text, path, placeholder)
+}
+
+// normalize applies all normalizers in the given order
+func normalize(text string, normalizers []Normalizer) string {
+	for _, n := range normalizers {
+		text = n.Apply(text)
+	}
+	return text
+}
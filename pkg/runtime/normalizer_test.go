@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizer_Validate(t *testing.T) {
+	assert.Nil(t, Normalizer{Name: StripANSI}.Validate())
+	assert.Nil(t, Normalizer{Regex: "[0-9]+", Replace: "<N>"}.Validate())
+	assert.EqualError(t, Normalizer{Name: "uppercase"}.Validate(), "normalizer uppercase does not exist")
+	assert.Contains(t, Normalizer{Regex: "[0-9"}.Validate().Error(), "invalid regex in normalize")
+}
+
+func TestNormalizer_StripANSI(t *testing.T) {
+	got := Normalizer{Name: StripANSI}.Apply("\x1b[1;31mfailed\x1b[0m \x1b]0;title\x07done")
+	assert.Equal(t, "failed done", got)
+}
+
+func TestNormalizer_CollapseWhitespace(t *testing.T) {
+	got := Normalizer{Name: CollapseWhitespace}.Apply("  a \t  b  \nc    d ")
+	assert.Equal(t, "a b\nc d", got)
+}
+
+func TestNormalizer_Lowercase(t *testing.T) {
+	assert.Equal(t, "hello", Normalizer{Name: Lowercase}.Apply("HeLLo"))
+}
+
+func TestNormalizer_Regex(t *testing.T) {
+	n := Normalizer{Regex: `\d{4}-\d{2}-\d{2}`, Replace: "<DATE>"}
+	assert.Equal(t, "started at <DATE>", n.Apply("started at 2020-01-31"))
+}
+
+func TestNewRegexNormalizer(t *testing.T) {
+	n, err := NewRegexNormalizer(`/tmp/[a-z]+`, "$TMPDIR/$1")
+	assert.Nil(t, err)
+	assert.NotNil(t, n.regex)
+	assert.Equal(t, "file: $TMPDIR/$1.txt", n.Apply("file: /tmp/out.txt"))
+
+	_, err = NewRegexNormalizer("[0-9", "")
+	assert.Contains(t, err.Error(), "invalid regex in normalize")
+}
+
+func TestNormalizer_ReplaceHome(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	got := Normalizer{Name: ReplaceHome}.Apply("config: " + home + "/.config")
+	assert.Equal(t, "config: $HOME/.config", got)
+}
+
+func TestNormalizer_ReplaceTmpDir(t *testing.T) {
+	got := Normalizer{Name: ReplaceTmpDir}.Apply("file: " + os.TempDir() + "/out.txt")
+	assert.Equal(t, "file: $TMPDIR/out.txt", got)
+}
+
+func TestNormalizer_MarshalYAML(t *testing.T) {
+	got, _ := Normalizer{Name: Lowercase}.MarshalYAML()
+	assert.Equal(t, "lowercase", got)
+
+	got, _ = Normalizer{Regex: "a", Replace: "b"}.MarshalYAML()
+	assert.Equal(t, map[string]string{"regex": "a", "replace": "b"}, got)
+}
+
+func Test_ValidateExpectedOut_Normalize(t *testing.T) {
+	got := validateExpectedOut("\x1b[32mOK\x1b[0m  id=42", ExpectedOut{
+		Normalize: []Normalizer{{Name: StripANSI}, {Name: CollapseWhitespace}, {Regex: `id=\d+`, Replace: "id=<ID>"}},
+		Exactly:   "OK id=<ID>",
+	})
+	assert.Empty(t, got)
+
+	got = validateExpectedOut("\x1b[32mOK\x1b[0m", ExpectedOut{
+		Normalize: []Normalizer{{Name: StripANSI}},
+		Exactly:   "FAIL",
+	})
+	assert.Len(t, got, 1)
+	assert.Contains(t, got[0].Diff, "-OK")
+}
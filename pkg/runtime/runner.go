@@ -37,6 +37,14 @@ func (r *Runner) Run(tests []TestCase) <-chan TestResult {
 			}
 
 			for _, n := range t.Nodes {
+				if !t.Skip && (t.RunIf != nil || t.SkipIf != nil) {
+					// conditions are checked once per node before the retries
+					if reason := skipReason(r.getExecutor(n), t); reason != "" {
+						out <- TestResult{TestCase: t, Skipped: true, Node: n, SkipReason: reason}
+						continue
+					}
+				}
+
 				result := TestResult{}
 				for i := 1; i <= t.Command.GetRetries(); i++ {
 
@@ -47,6 +55,9 @@ func (r *Runner) Run(tests []TestCase) <-chan TestResult {
 
 					e := r.getExecutor(n)
 					result = e.Execute(t)
+					if result.TestCase.Result.Error == nil && len(t.Expected.Validators) > 0 {
+						result = validateWithCommands(e, result)
+					}
 					result.Node = n
 					result.Tries = i
 
@@ -25,6 +25,25 @@ func Test_RunnerExexcute(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func Test_Runner_SkipsTestsByCondition(t *testing.T) {
+	tests := []TestCase{{
+		Title:   "Conditional",
+		Command: CommandUnderTest{Cmd: "echo hello"},
+		RunIf:   &Condition{OS: []string{"plan9"}},
+	}}
+	r := Runner{Nodes: getExampleNodes()}
+
+	var results []TestResult
+	for tr := range r.Run(tests) {
+		results = append(results, tr)
+	}
+
+	assert.Len(t, results, 1)
+	assert.True(t, results[0].Skipped)
+	assert.Equal(t, "local", results[0].Node)
+	assert.Contains(t, results[0].SkipReason, "run-if: os ")
+}
+
 func Test_getExecutor(t *testing.T) {
 	r := Runner{
 		Nodes: getExampleNodes(),
@@ -4,6 +4,8 @@ import (
 	"log"
 	"sort"
 	"time"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
 )
 
 // Constants for defining the various tested properties
@@ -11,7 +13,9 @@ const (
 	ExitCode  = "ExitCode"
 	Stdout    = "Stdout"
 	Stderr    = "Stderr"
+	Output    = "Output"
 	LineCount = "LineCount"
+	Validator = "Validator"
 )
 
 type Filters []string
@@ -49,13 +53,20 @@ type EventHandler struct {
 
 // TestCase represents a test case which will be executed by the runtime
 type TestCase struct {
-	Title    string
-	Command  CommandUnderTest
-	Expected Expected
-	Result   CommandResult
-	Nodes    []string
-	FileName string
-	Skip     bool
+	Title string
+	// Description explains what the test guards, it is displayed with failures
+	Description string
+	Command     CommandUnderTest
+	Expected    Expected
+	Result      CommandResult
+	Nodes       []string
+	FileName    string
+	Skip        bool
+	// Tags select the test with a TagFilter, they include the tags of the suite
+	Tags []string
+	// RunIf and SkipIf are checked before the test is executed on a node, see Condition
+	RunIf  *Condition
+	SkipIf *Condition
 }
 
 // GlobalTestConfig represents the configuration for a test
@@ -67,6 +78,7 @@ type GlobalTestConfig struct {
 	Interval   string
 	InheritEnv bool
 	Nodes      []string
+	MaxOutput  string
 }
 
 // ResultStatus represents the status code of a test result
@@ -77,29 +89,60 @@ type CommandResult struct {
 	Status            ResultStatus
 	Stdout            string
 	Stderr            string
+	Output            string
 	ExitCode          int
 	FailureProperties []string
 	Error             error
+	// captures holds the complete output if it exceeded the max-output limit
+	captures *outputCaptures
 }
 
 // Expected is the expected output of the command under test
 type Expected struct {
-	Stdout    ExpectedOut
-	Stderr    ExpectedOut
+	Stdout ExpectedOut
+	Stderr ExpectedOut
+	// Output is validated against the combined stdout and stderr in the order they were written
+	Output    ExpectedOut
 	LineCount int
 	ExitCode  int
+	// ExitCodes allows lists, ranges or negations of exit codes, it takes precedence over ExitCode if set
+	ExitCodes *matcher.ExitCodeExpectation
+	// Validators are commands which validate the result, see validateWithCommands
+	Validators []string
 }
 
-// ExpectedOut represents the assertions on stdout and stderr
+// ExpectedOut represents the assertions on stdout and stderr,
+// the output is normalized by all Normalize steps before the assertions are executed
 type ExpectedOut struct {
-	Contains    []string          `yaml:"contains,omitempty"`
-	Lines       map[int]string    `yaml:"lines,omitempty"`
-	Exactly     string            `yaml:"exactly,omitempty"`
-	LineCount   int               `yaml:"line-count,omitempty"`
-	NotContains []string          `yaml:"not-contains,omitempty"`
-	JSON        map[string]string `yaml:"json,omitempty"`
-	XML         map[string]string `yaml:"xml,omitempty"`
-	File        string            `yaml:"file,omitempty"`
+	Contains []string `yaml:"contains,omitempty"`
+	// ContainsCount holds the counted texts of contains, they are defined in the contains list of the suite
+	ContainsCount   []matcher.ContainsCount `yaml:"-"`
+	ContainsInOrder []string                `yaml:"contains-in-order,omitempty"`
+	// Transcript matches the output line by line, an empty list expects an empty output
+	Transcript []string              `yaml:"transcript,omitempty"`
+	AnyOf      []matcher.Expectation `yaml:"any-of,omitempty"`
+	AllOf      []matcher.Expectation `yaml:"all-of,omitempty"`
+	Not        matcher.Expectation   `yaml:"not,omitempty"`
+	// Message is displayed with every failed assertion, Messages with the failure of the assertion with the given key
+	Message     string                    `yaml:"message,omitempty"`
+	Messages    map[string]string         `yaml:"messages,omitempty"`
+	Lines       map[int]string            `yaml:"lines,omitempty"`
+	Exactly     string                    `yaml:"exactly,omitempty"`
+	LineCount   int                       `yaml:"line-count,omitempty"`
+	NotContains []string                  `yaml:"not-contains,omitempty"`
+	JSON        map[string]string         `yaml:"json,omitempty"`
+	XML         map[string]string         `yaml:"xml,omitempty"`
+	File        string                    `yaml:"file,omitempty"`
+	Table       *matcher.TableExpectation `yaml:"table,omitempty"`
+	ByteSize    *int                      `yaml:"byte-size,omitempty"`
+	SHA256      string                    `yaml:"sha256,omitempty"`
+	MD5         string                    `yaml:"md5,omitempty"`
+	BinaryFile  string                    `yaml:"binary-file,omitempty"`
+	Normalize   []Normalizer              `yaml:"normalize,omitempty"`
+	// RawOutput uses the raw variants of the assertions, see matcher.Assertion
+	RawOutput bool `yaml:"-"`
+	// Custom holds the values of assertions which were added with matcher.RegisterAssertion
+	Custom map[string]interface{} `yaml:",inline"`
 }
 
 // CommandUnderTest represents the command under test
@@ -107,20 +150,32 @@ type CommandUnderTest struct {
 	Cmd        string
 	InheritEnv bool
 	Env        map[string]string
+	// LiteralEnv is added to Env, variables like $HOME in its values are not expanded
+	LiteralEnv map[string]string
 	Dir        string
 	Timeout    string
 	Retries    int
 	Interval   string
+	Stdin      string
+	// MaxOutput limits the captured output of each stream, see outputCapture
+	MaxOutput string
+	// RawOutput keeps the output as it was produced, otherwise surrounding whitespace is removed
+	RawOutput bool
 }
 
 // TestResult represents the TestCase and the ValidationResult
 type TestResult struct {
 	TestCase         TestCase
 	ValidationResult ValidationResult
-	FailedProperty   string
-	Tries            int
-	Node             string
-	Skipped          bool
+	// FailedProperty is the property of the first failure
+	FailedProperty string
+	// Failures holds every failed assertion of the test
+	Failures []Failure
+	Tries    int
+	Node     string
+	Skipped  bool
+	// SkipReason is the run-if or skip-if check which skipped the test
+	SkipReason string
 }
 
 // Result respresents the aggregation of all TestResults/summary of a runtime
@@ -1,7 +1,6 @@
 package runtime
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"net"
@@ -55,6 +54,15 @@ func (e SSHExecutor) Execute(test TestCase) TestResult {
 		panic("Inherit env is not supported viá SSH")
 	}
 
+	captures, err := newOutputCaptures(test)
+	if err != nil {
+		test.Result = CommandResult{Error: err}
+		return TestResult{
+			TestCase: test,
+		}
+	}
+	defer captures.Close()
+
 	// initialize auth methods with pass auth method as the default
 	authMethods := []ssh.AuthMethod{
 		ssh.Password(e.Password),
@@ -88,12 +96,13 @@ func (e SSHExecutor) Execute(test TestCase) TestResult {
 	}
 	defer session.Close()
 
-	var stdoutBuffer bytes.Buffer
-	var stderrBuffer bytes.Buffer
-	session.Stdout = &stdoutBuffer
-	session.Stderr = &stderrBuffer
+	session.Stdout = captures.StdoutWriter()
+	session.Stderr = captures.StderrWriter()
+	if test.Command.Stdin != "" {
+		session.Stdin = strings.NewReader(test.Command.Stdin)
+	}
 
-	for k, v := range test.Command.Env {
+	for k, v := range mergeEnv(test.Command.Env, test.Command.LiteralEnv) {
 		err := session.Setenv(k, v)
 		if err != nil {
 			test.Result = CommandResult{
@@ -128,11 +137,7 @@ func (e SSHExecutor) Execute(test TestCase) TestResult {
 		}
 	}
 
-	test.Result = CommandResult{
-		ExitCode: exitCode,
-		Stdout:   strings.TrimSpace(strings.ReplaceAll(stdoutBuffer.String(), "\r\n", "\n")),
-		Stderr:   strings.TrimSpace(strings.ReplaceAll(stderrBuffer.String(), "\r\n", "\n")),
-	}
+	test.Result = captures.Result(test, exitCode)
 
 	log.Println("title: '"+test.Title+"'", " ExitCode: ", test.Result.ExitCode)
 	log.Println("title: '"+test.Title+"'", " Stdout: ", test.Result.Stdout)
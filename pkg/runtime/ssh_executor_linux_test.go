@@ -55,6 +55,27 @@ func Test_SSHExecutor(t *testing.T) {
 	assert.Equal(t, "test", got.TestCase.Result.Stdout)
 }
 
+func Test_SSHExecutor_CombinedOutput(t *testing.T) {
+	if !isSSHTestsEnabled() {
+		return
+	}
+
+	s := createExecutor()
+
+	test := TestCase{
+		Command: CommandUnderTest{
+			Cmd: "echo first; sleep 0.1; echo error >&2; sleep 0.1; echo last",
+		},
+		Expected: Expected{
+			Output: ExpectedOut{Exactly: "first\nerror\nlast"},
+		},
+	}
+	got := s.Execute(test)
+
+	assert.True(t, got.ValidationResult.Success)
+	assert.Equal(t, "first\nlast", got.TestCase.Result.Stdout)
+}
+
 func Test_SSHExecutor_WithDir(t *testing.T) {
 	if !isSSHTestsEnabled() {
 		return
@@ -0,0 +1,202 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tagOperators are the characters which can not be used in tags
+const tagOperators = "()!&|"
+
+// TagExpression is a boolean expression of tags, i.e. smoke && !slow.
+// Tags are combined with && (and), || (or), ! (not) and parentheses, && binds stronger than ||.
+type TagExpression struct {
+	source string
+	root   tagNode
+}
+
+// TagFilter selects tests by their tags.
+// A test is selected if it matches any of the included expressions, or if there are none,
+// and none of the excluded expressions.
+type TagFilter struct {
+	Include []TagExpression
+	Exclude []TagExpression
+}
+
+type tagNode interface {
+	match(tags map[string]bool) bool
+}
+
+type tagName string
+type tagNot struct{ node tagNode }
+type tagAnd struct{ left, right tagNode }
+type tagOr struct{ left, right tagNode }
+
+func (t tagName) match(tags map[string]bool) bool { return tags[string(t)] }
+func (t tagNot) match(tags map[string]bool) bool  { return !t.node.match(tags) }
+func (t tagAnd) match(tags map[string]bool) bool  { return t.left.match(tags) && t.right.match(tags) }
+func (t tagOr) match(tags map[string]bool) bool   { return t.left.match(tags) || t.right.match(tags) }
+
+// ValidateTag checks that the tag can be used in a TagExpression
+func ValidateTag(tag string) error {
+	if tag == "" || strings.ContainsAny(tag, tagOperators) || strings.IndexFunc(tag, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid tag '%s', tags must not be empty or contain whitespace or any of %s", tag, tagOperators)
+	}
+	return nil
+}
+
+// ParseTagExpression parses a TagExpression
+func ParseTagExpression(expr string) (TagExpression, error) {
+	p := &tagParser{tokens: tokenizeTags(expr)}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected '%s'", p.tokens[p.pos])
+	}
+	if err != nil {
+		return TagExpression{}, fmt.Errorf("invalid tag expression '%s': %s", expr, err)
+	}
+	return TagExpression{source: expr, root: root}, nil
+}
+
+// Match checks if the tags match the expression
+func (e TagExpression) Match(tags []string) bool {
+	set := make(map[string]bool)
+	for _, t := range tags {
+		set[t] = true
+	}
+	return e.root.match(set)
+}
+
+func (e TagExpression) String() string {
+	return e.source
+}
+
+// NewTagFilter parses the included and excluded expressions
+func NewTagFilter(include []string, exclude []string) (TagFilter, error) {
+	f := TagFilter{}
+	for _, expr := range include {
+		e, err := ParseTagExpression(expr)
+		if err != nil {
+			return TagFilter{}, err
+		}
+		f.Include = append(f.Include, e)
+	}
+	for _, expr := range exclude {
+		e, err := ParseTagExpression(expr)
+		if err != nil {
+			return TagFilter{}, err
+		}
+		f.Exclude = append(f.Exclude, e)
+	}
+	return f, nil
+}
+
+// Match checks if a test with the tags is selected by the filter
+func (f TagFilter) Match(tags []string) bool {
+	for _, e := range f.Exclude {
+		if e.Match(tags) {
+			return false
+		}
+	}
+
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, e := range f.Include {
+		if e.Match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenizeTags splits the expression into operators and tags
+func tokenizeTags(expr string) []string {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')' || c == '!':
+			tokens = append(tokens, expr[i:i+1])
+			i++
+		case strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case c == '&' || c == '|':
+			tokens = append(tokens, expr[i:i+1])
+			i++
+		default:
+			end := i
+			for end < len(expr) && !strings.ContainsRune(tagOperators+" \t\n", rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+		}
+	}
+	return tokens
+}
+
+type tagParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *tagParser) parseOr() (tagNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var right tagNode
+		right, err = p.parseAnd()
+		left = tagOr{left, right}
+	}
+	return left, err
+}
+
+func (p *tagParser) parseAnd() (tagNode, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var right tagNode
+		right, err = p.parseUnary()
+		left = tagAnd{left, right}
+	}
+	return left, err
+}
+
+func (p *tagParser) parseUnary() (tagNode, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected a tag at the end")
+	case "!":
+		p.pos++
+		node, err := p.parseUnary()
+		return tagNot{node}, err
+	case "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		p.pos++
+		return node, nil
+	case ")", "&&", "||", "&", "|":
+		return nil, fmt.Errorf("expected a tag, got '%s'", token)
+	}
+
+	p.pos++
+	return tagName(token), nil
+}
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseTagExpression(t *testing.T) {
+	tests := []struct {
+		expr  string
+		tags  []string
+		match bool
+	}{
+		{"smoke", []string{"smoke"}, true},
+		{"smoke", []string{"slow"}, false},
+		{"smoke && !slow", []string{"smoke"}, true},
+		{"smoke && !slow", []string{"smoke", "slow"}, false},
+		{"smoke || slow && linux", []string{"smoke"}, true},
+		{"(smoke || slow) && linux", []string{"smoke"}, false},
+		{"(smoke || slow) && linux", []string{"slow", "linux"}, true},
+		{"!!smoke", []string{"smoke"}, true},
+		{"api:v2&&!os/windows", []string{"api:v2"}, true},
+	}
+
+	for _, tt := range tests {
+		e, err := ParseTagExpression(tt.expr)
+		assert.Nil(t, err, tt.expr)
+		assert.Equal(t, tt.match, e.Match(tt.tags), "%s with %v", tt.expr, tt.tags)
+		assert.Equal(t, tt.expr, e.String())
+	}
+}
+
+func Test_ParseTagExpression_Errors(t *testing.T) {
+	errors := map[string]string{
+		"":             "invalid tag expression '': expected a tag at the end",
+		"smoke &&":     "invalid tag expression 'smoke &&': expected a tag at the end",
+		"smoke & slow": "invalid tag expression 'smoke & slow': unexpected '&'",
+		"smoke slow":   "invalid tag expression 'smoke slow': unexpected 'slow'",
+		"(smoke":       "invalid tag expression '(smoke': expected ')'",
+		"smoke)":       "invalid tag expression 'smoke)': unexpected ')'",
+		"|| smoke":     "invalid tag expression '|| smoke': expected a tag, got '||'",
+		"!(smoke || )": "invalid tag expression '!(smoke || )': expected a tag, got ')'",
+	}
+
+	for expr, msg := range errors {
+		_, err := ParseTagExpression(expr)
+		assert.EqualError(t, err, msg, expr)
+	}
+}
+
+func Test_TagFilter(t *testing.T) {
+	f, err := NewTagFilter([]string{"smoke", "api"}, []string{"slow"})
+	assert.Nil(t, err)
+
+	assert.True(t, f.Match([]string{"smoke"}))
+	assert.True(t, f.Match([]string{"api", "linux"}))
+	assert.False(t, f.Match([]string{"smoke", "slow"}))
+	assert.False(t, f.Match([]string{"linux"}))
+	assert.False(t, f.Match(nil))
+
+	f, _ = NewTagFilter(nil, []string{"slow"})
+	assert.True(t, f.Match(nil))
+	assert.False(t, f.Match([]string{"slow"}))
+
+	_, err = NewTagFilter(nil, []string{"!"})
+	assert.EqualError(t, err, "invalid tag expression '!': expected a tag at the end")
+}
+
+func Test_ValidateTag(t *testing.T) {
+	assert.Nil(t, ValidateTag("api:v2"))
+	assert.NotNil(t, ValidateTag(""))
+	assert.NotNil(t, ValidateTag("two words"))
+	assert.EqualError(t, ValidateTag("a|b"), "invalid tag 'a|b', tags must not be empty or contain whitespace or any of ()!&|")
+}
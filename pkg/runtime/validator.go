@@ -3,7 +3,6 @@ package runtime
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/commander-cli/commander/v2/pkg/matcher"
 )
@@ -21,156 +20,144 @@ func newValidationResult(m matcher.MatcherResult) ValidationResult {
 	}
 }
 
+// Failure represents a single failed assertion of a test
+type Failure struct {
+	Property string
+	Diff     string
+	// Message is the custom message of the failed assertion
+	Message string
+}
+
 // Validate validates the test results with the expected values
-// The test should hold the result and expected to validate the result
+// The test should hold the result and expected to validate the result.
+// All expectations are validated, every failed assertion is collected in TestResult.Failures.
 func Validate(test TestCase) TestResult {
-	equalMatcher := matcher.NewMatcher(matcher.Equal)
+	var failures []Failure
+
+	stdout, stderr, output := test.Result.openers()
 
 	log.Println("title: '"+test.Title+"'", " Stdout-Expected: ", test.Expected.Stdout)
-	matcherResult := validateExpectedOut(test.Result.Stdout, test.Expected.Stdout)
-	log.Println("title: '"+test.Title+"'", " Stdout-Result: ", matcherResult.Success)
-	if !matcherResult.Success {
-		return TestResult{
-			ValidationResult: newValidationResult(matcherResult),
-			TestCase:         test,
-			FailedProperty:   Stdout,
-		}
+	for _, f := range validateOutput(test.Result.Stdout, stdout, test.Expected.Stdout) {
+		f.Property = Stdout
+		failures = append(failures, f)
 	}
+	log.Println("title: '"+test.Title+"'", " Stdout-Result: ", len(failures) == 0)
 
 	log.Println("title: '"+test.Title+"'", " Stderr-Expected: ", test.Expected.Stderr)
-	matcherResult = validateExpectedOut(test.Result.Stderr, test.Expected.Stderr)
-	log.Println("title: '"+test.Title+"'", " Stderr-Result: ", matcherResult.Success)
-	if !matcherResult.Success {
-		return TestResult{
-			ValidationResult: newValidationResult(matcherResult),
-			TestCase:         test,
-			FailedProperty:   Stderr,
-		}
+	stderrFailures := validateOutput(test.Result.Stderr, stderr, test.Expected.Stderr)
+	for _, f := range stderrFailures {
+		f.Property = Stderr
+		failures = append(failures, f)
 	}
+	log.Println("title: '"+test.Title+"'", " Stderr-Result: ", len(stderrFailures) == 0)
 
-	log.Println("title: '"+test.Title+"'", " Exit-Expected: ", test.Expected.ExitCode)
-	matcherResult = equalMatcher.Match(test.Result.ExitCode, test.Expected.ExitCode)
+	log.Println("title: '"+test.Title+"'", " Output-Expected: ", test.Expected.Output)
+	outputFailures := validateOutput(test.Result.Output, output, test.Expected.Output)
+	for _, f := range outputFailures {
+		f.Property = Output
+		failures = append(failures, f)
+	}
+	log.Println("title: '"+test.Title+"'", " Output-Result: ", len(outputFailures) == 0)
+
+	var matcherResult matcher.MatcherResult
+	if test.Expected.ExitCodes != nil {
+		log.Println("title: '"+test.Title+"'", " Exit-Expected: ", test.Expected.ExitCodes)
+		matcherResult = matcher.NewMatcher(matcher.ExitCode).Match(test.Result.ExitCode, *test.Expected.ExitCodes)
+	} else {
+		log.Println("title: '"+test.Title+"'", " Exit-Expected: ", test.Expected.ExitCode)
+		matcherResult = matcher.NewMatcher(matcher.Equal).Match(test.Result.ExitCode, test.Expected.ExitCode)
+	}
 	log.Println("title: '"+test.Title+"'", " Exit-Result: ", matcherResult.Success)
 	if !matcherResult.Success {
-		return TestResult{
-			ValidationResult: newValidationResult(matcherResult),
-			TestCase:         test,
-			FailedProperty:   ExitCode,
-		}
+		failures = append(failures, Failure{Property: ExitCode, Diff: matcherResult.Diff})
 	}
 
-	return TestResult{
-		ValidationResult: newValidationResult(matcherResult),
-		TestCase:         test,
-	}
+	return newTestResult(test, failures)
 }
 
-func validateExpectedOut(got string, expected ExpectedOut) matcher.MatcherResult {
-	var m matcher.Matcher
-	var result matcher.MatcherResult
-	result.Success = true
-
-	if expected.Exactly != "" {
-		m = matcher.NewMatcher(matcher.Text)
-		if result = m.Match(got, expected.Exactly); !result.Success {
-			return result
-		}
-	}
-
-	m = matcher.NewMatcher(matcher.Contains)
-	for _, c := range expected.Contains {
-		if result = m.Match(got, c); !result.Success {
-			return result
-		}
-	}
-
-	if expected.LineCount != 0 {
-		result = validateExpectedLineCount(got, expected)
-		if !result.Success {
-			return result
+// newTestResult creates a TestResult from the given failures,
+// FailedProperty and ValidationResult hold the first failure
+func newTestResult(test TestCase, failures []Failure) TestResult {
+	test.Result.FailureProperties = nil
+	for _, f := range failures {
+		if !containsString(test.Result.FailureProperties, f.Property) {
+			test.Result.FailureProperties = append(test.Result.FailureProperties, f.Property)
 		}
 	}
 
-	if len(expected.Lines) > 0 {
-		result = validateExpectedLines(got, expected)
-		if !result.Success {
-			return result
-		}
-	}
-
-	m = matcher.NewMatcher(matcher.NotContains)
-	for _, c := range expected.NotContains {
-		if result = m.Match(got, c); !result.Success {
-			return result
-		}
-	}
-
-	m = matcher.NewMatcher(matcher.JSON)
-	for i, v := range expected.JSON {
-		if result = m.Match(got, map[string]string{i: v}); !result.Success {
-			return result
-		}
-	}
-
-	m = matcher.NewMatcher(matcher.XML)
-	for i, v := range expected.XML {
-		if result = m.Match(got, map[string]string{i: v}); !result.Success {
-			return result
-		}
+	tr := TestResult{
+		ValidationResult: ValidationResult{Success: len(failures) == 0},
+		TestCase:         test,
+		Failures:         failures,
 	}
 
-	if expected.File != "" {
-		m = matcher.NewMatcher(matcher.File)
-		if result = m.Match(got, expected.File); !result.Success {
-			return result
-		}
+	if len(failures) > 0 {
+		tr.FailedProperty = failures[0].Property
+		tr.ValidationResult.Diff = failures[0].Diff
 	}
 
-	return result
+	return tr
 }
 
-func validateExpectedLineCount(got string, expected ExpectedOut) matcher.MatcherResult {
-	m := matcher.NewMatcher(matcher.Equal)
-	count := strings.Count(got, getLineBreak()) + 1
-
-	if got == "" {
-		count = 0
+// validateExpectedOut executes all registered assertions which are set in expected
+// and returns the results of all failed assertions.
+// The assertions are executed in the order of their registration.
+func validateExpectedOut(got string, expected ExpectedOut) []matcher.MatcherResult {
+	var results []matcher.MatcherResult
+	for _, f := range validateOutput(got, nil, expected) {
+		results = append(results, matcher.MatcherResult{Success: false, Diff: f.Diff})
 	}
-
-	return m.Match(count, expected.LineCount)
+	return results
 }
 
-func validateExpectedLines(got string, expected ExpectedOut) matcher.MatcherResult {
-	m := matcher.NewMatcher(matcher.Equal)
-	actualLines := strings.Split(got, getLineBreak())
-	result := matcher.MatcherResult{Success: true}
+// validateOutput validates the output like validateExpectedOut and returns the failures with their custom messages,
+// the property of the failures is not set.
+// If the output was truncated, open opens the complete output which is used by all matchers
+// which implement matcher.StreamMatcher. All other assertions and assertions on normalized output
+// fail, they would only see the truncated output.
+func validateOutput(got string, open matcher.OpenFunc, expected ExpectedOut) []Failure {
+	var failures []Failure
+
+	got = normalize(got, expected.Normalize)
+
+	values := expected.Values()
+	for _, a := range matcher.Assertions() {
+		v, ok := values[a.Key]
+		if !ok {
+			continue
+		}
 
-	for key, expectedLine := range expected.Lines {
-		// line number 0 or below 0
-		if key <= 0 {
-			panic(fmt.Sprintf("Invalid line number given %d", key))
+		m := a.Matcher
+		if expected.RawOutput && a.RawMatcher != nil {
+			m = a.RawMatcher
 		}
 
-		// line number exceeds result set
-		if key > len(actualLines) {
-			return matcher.MatcherResult{
-				Success: false,
-				Diff: fmt.Sprintf(
-					"Line number %d does not exists in result: \n\n%s",
-					key,
-					strings.Join(actualLines, "\n"),
-				),
+		var result matcher.MatcherResult
+		sm, stream := m.(matcher.StreamMatcher)
+		switch {
+		case open != nil && stream && len(expected.Normalize) == 0:
+			result = sm.MatchStream(open, got, v)
+		case open != nil:
+			result = matcher.MatcherResult{
+				Diff: fmt.Sprintf("output was truncated by max-output, %s needs the complete output", a.Key),
 			}
+		default:
+			result = m.Match(got, v)
 		}
 
-		if result = m.Match(actualLines[key-1], expectedLine); !result.Success {
-			return result
+		if !result.Success {
+			failures = append(failures, Failure{Diff: result.Diff, Message: expected.message(a.Key)})
 		}
 	}
 
-	return result
+	return failures
 }
 
-func getLineBreak() string {
-	return "\n"
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
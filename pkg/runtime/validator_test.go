@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,79 @@ func Test_ValidateExitCodeShouldFail(t *testing.T) {
 	assert.Equal(t, "ExitCode", got.FailedProperty)
 }
 
+func Test_ValidateExitCodes(t *testing.T) {
+	test := getExampleTest()
+	test.Expected.ExitCodes = &matcher.ExitCodeExpectation{Ranges: []matcher.ExitCodeRange{{Min: 1, Max: 125}}}
+
+	got := Validate(test)
+
+	assert.False(t, got.ValidationResult.Success)
+	assert.Equal(t, "ExitCode", got.FailedProperty)
+	assert.Contains(t, got.ValidationResult.Diff, "to match\n\n1-125")
+
+	test.Result.ExitCode = 2
+	got = Validate(test)
+	assert.True(t, got.ValidationResult.Success)
+}
+
+func Test_ValidateOutputShouldFail(t *testing.T) {
+	test := getExampleTest()
+	test.Expected.Output = ExpectedOut{Exactly: "hello\nerror"}
+	test.Result.Output = "error\nhello"
+
+	got := Validate(test)
+
+	assert.False(t, got.ValidationResult.Success)
+	assert.Equal(t, Output, got.FailedProperty)
+}
+
+func Test_ValidateCollectsAllFailures(t *testing.T) {
+	test := getExampleTest()
+	test.Expected.Stdout.Contains = []string{"hello", "world"}
+	test.Result = CommandResult{
+		Stdout:   "hello\nline2",
+		Stderr:   "warning",
+		ExitCode: 1,
+	}
+
+	got := Validate(test)
+
+	assert.False(t, got.ValidationResult.Success)
+	assert.Equal(t, "Stdout", got.FailedProperty)
+	assert.Equal(t, []string{Stdout, Stderr, ExitCode}, got.TestCase.Result.FailureProperties)
+
+	var properties []string
+	for _, f := range got.Failures {
+		properties = append(properties, f.Property)
+	}
+	// stdout: exactly, contains, line-count; stderr: exactly, contains, lines; exit-code
+	assert.Equal(t, []string{Stdout, Stdout, Stdout, Stderr, Stderr, Stderr, ExitCode}, properties)
+	assert.Equal(t, got.Failures[0].Diff, got.ValidationResult.Diff)
+}
+
+func Test_ValidateAddsMessages(t *testing.T) {
+	test := TestCase{
+		Expected: Expected{
+			Stdout: ExpectedOut{
+				Contains:  []string{"ready"},
+				LineCount: 3,
+				Message:   "server did not start",
+				Messages:  map[string]string{"line-count": "banner is missing"},
+			},
+			Stderr: ExpectedOut{Contains: []string{"warning"}},
+		},
+		Result: CommandResult{Stdout: "starting", Stderr: "error"},
+	}
+
+	got := Validate(test)
+
+	assert.Equal(t, []Failure{
+		{Property: Stdout, Diff: got.Failures[0].Diff, Message: "server did not start"},
+		{Property: Stdout, Diff: got.Failures[1].Diff, Message: "banner is missing"},
+		{Property: Stderr, Diff: got.Failures[2].Diff},
+	}, got.Failures)
+}
+
 func Test_ValidateExpectedOut_Contains_Fails(t *testing.T) {
 	value := `test`
 
@@ -81,8 +155,14 @@ to contain
 not-exists
 `
 
-	assert.False(t, got.Success)
-	assert.Equal(t, diff, got.Diff)
+	assert.Len(t, got, 1)
+	assert.Equal(t, diff, got[0].Diff)
+}
+
+func Test_ValidateExpectedOut_Transcript(t *testing.T) {
+	assert.Empty(t, validateExpectedOut("v1.2\nok", ExpectedOut{Transcript: []string{`v\d\.\d (re)`, "ok"}}))
+	assert.Empty(t, validateExpectedOut("", ExpectedOut{Transcript: []string{}}))
+	assert.Len(t, validateExpectedOut("output", ExpectedOut{Transcript: []string{}}), 1)
 }
 
 func Test_ValidateExpectedOut_MatchLines(t *testing.T) {
@@ -93,8 +173,7 @@ output`
 
 	got := validateExpectedOut(value, ExpectedOut{Lines: map[int]string{1: "my", 3: "line"}})
 
-	assert.True(t, got.Success)
-	assert.Empty(t, got.Diff)
+	assert.Empty(t, got)
 }
 
 func Test_ValidateExpectedOut_MatchLines_ExpectedLineDoesNotExists(t *testing.T) {
@@ -102,11 +181,11 @@ func Test_ValidateExpectedOut_MatchLines_ExpectedLineDoesNotExists(t *testing.T)
 
 	got := validateExpectedOut(value, ExpectedOut{Lines: map[int]string{2: "my"}})
 
-	assert.False(t, got.Success)
+	assert.Len(t, got, 1)
 	diff := `Line number 2 does not exists in result: 
 
 test`
-	assert.Equal(t, diff, got.Diff)
+	assert.Equal(t, diff, got[0].Diff)
 }
 
 func Test_ValidateExpectedOut_MatchLines_Fails(t *testing.T) {
@@ -116,14 +195,14 @@ line 3`
 
 	got := validateExpectedOut(value, ExpectedOut{Lines: map[int]string{2: "line 3"}})
 
-	assert.False(t, got.Success)
+	assert.Len(t, got, 1)
 	diff := `--- Got
 +++ Expected
 @@ -1 +1 @@
 -line 2
 +line 3
 `
-	assert.Equal(t, diff, got.Diff)
+	assert.Equal(t, diff, got[0].Diff)
 }
 
 func Test_ValidateExpectedOut_LineCount_Fails(t *testing.T) {
@@ -131,14 +210,14 @@ func Test_ValidateExpectedOut_LineCount_Fails(t *testing.T) {
 
 	got := validateExpectedOut(value, ExpectedOut{LineCount: 2})
 
-	assert.False(t, got.Success)
+	assert.Len(t, got, 1)
 	diff := `--- Got
 +++ Expected
 @@ -1 +1 @@
 -0
 +2
 `
-	assert.Equal(t, diff, got.Diff)
+	assert.Equal(t, diff, got[0].Diff)
 }
 
 func Test_ValidateExpectedOut_NotContains_Fails(t *testing.T) {
@@ -155,21 +234,16 @@ to not contain
 
 contains
 `
-	assert.False(t, got.Success)
-	assert.Equal(t, diff, got.Diff)
+	assert.Len(t, got, 1)
+	assert.Equal(t, diff, got[0].Diff)
 }
 
-func Test_ValidateExpectedOut_PanicIfLineDoesNotExist(t *testing.T) {
-	defer func() {
-		r := recover()
-		if r != nil {
-			assert.Equal(t, "Invalid line number given 0", r)
-		}
-		assert.NotNil(t, r)
-	}()
-
+func Test_ValidateExpectedOut_FailIfLineNumberIsInvalid(t *testing.T) {
 	value := `my`
-	_ = validateExpectedOut(value, ExpectedOut{Lines: map[int]string{0: "my"}})
+	got := validateExpectedOut(value, ExpectedOut{Lines: map[int]string{0: "my"}})
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, "Invalid line number 0, line numbers start at 1", got[0].Diff)
 }
 
 func Test_ValidateExpectedOut_ValidateJSON(t *testing.T) {
@@ -181,7 +255,7 @@ func Test_ValidateExpectedOut_ValidateJSON(t *testing.T) {
 }
 `
 	r := validateExpectedOut(json, ExpectedOut{JSON: map[string]string{"object.attr": "test"}})
-	assert.True(t, r.Success)
+	assert.Empty(t, r)
 
 	diff := `Expected json path "object.attr" with result
 
@@ -191,8 +265,8 @@ to be equal to
 
 test`
 	r = validateExpectedOut(json, ExpectedOut{JSON: map[string]string{"object.attr": "no"}})
-	assert.False(t, r.Success)
-	assert.Equal(t, diff, r.Diff)
+	assert.Len(t, r, 1)
+	assert.Equal(t, diff, r[0].Diff)
 }
 
 func Test_ValidateExpectedOut_ValidateFile(t *testing.T) {
@@ -201,8 +275,7 @@ func Test_ValidateExpectedOut_ValidateFile(t *testing.T) {
 		return []byte(content), nil
 	}
 	r := validateExpectedOut(content, ExpectedOut{File: "fake.txt"})
-	assert.True(t, r.Success)
-	assert.Equal(t, "", r.Diff)
+	assert.Empty(t, r)
 
 	diff := `--- Got
 +++ Expected
@@ -212,8 +285,8 @@ func Test_ValidateExpectedOut_ValidateFile(t *testing.T) {
 `
 
 	r = validateExpectedOut(content+"\nline two", ExpectedOut{File: "fake.txt"})
-	assert.False(t, r.Success)
-	assert.Equal(t, diff, r.Diff)
+	assert.Len(t, r, 1)
+	assert.Equal(t, diff, r[0].Diff)
 
 	matcher.ReadFile = os.ReadFile
 }
@@ -224,8 +297,7 @@ func Test_ValidateExpectedOut_ValidateXML(t *testing.T) {
 </book>`
 
 	r := validateExpectedOut(xml, ExpectedOut{XML: map[string]string{"/book//author": "J. R. R. Tolkien"}})
-	assert.True(t, r.Success)
-	assert.Equal(t, "", r.Diff)
+	assert.Empty(t, r)
 
 	diff := `Expected xml path "/book//author" with result
 
@@ -235,12 +307,12 @@ to be equal to
 
 J. R. R. Tolkien`
 	r = validateExpectedOut(xml, ExpectedOut{XML: map[string]string{"/book//author": "Joanne K. Rowling"}})
-	assert.False(t, r.Success)
-	assert.Equal(t, diff, r.Diff)
+	assert.Len(t, r, 1)
+	assert.Equal(t, diff, r[0].Diff)
 
 	r = validateExpectedOut(xml, ExpectedOut{XML: map[string]string{"/book//title": "J. R. R. Tolkien"}})
-	assert.False(t, r.Success)
-	assert.Equal(t, `Query "/book//title" did not match a path`, r.Diff)
+	assert.Len(t, r, 1)
+	assert.Equal(t, `Query "/book//title" did not match a path`, r[0].Diff)
 }
 
 func getExampleTest() TestCase {
@@ -270,3 +342,56 @@ func getExampleTest() TestCase {
 	}
 	return test
 }
+
+func Test_ValidateExpectedOut_ValidateTable(t *testing.T) {
+	table := "name,status\nweb,Running"
+
+	r := validateExpectedOut(table, ExpectedOut{Table: &matcher.TableExpectation{
+		Rows: []map[string]string{{"name": "web"}},
+	}})
+	assert.Empty(t, r)
+
+	r = validateExpectedOut(table, ExpectedOut{Table: &matcher.TableExpectation{
+		Rows: []map[string]string{{"name": "db"}},
+	}})
+	assert.Len(t, r, 1)
+	assert.Contains(t, r[0].Diff, "Expected table to contain a row with")
+}
+
+type suffixMatcher struct{}
+
+func (m suffixMatcher) Match(got interface{}, expected interface{}) matcher.MatcherResult {
+	return matcher.MatcherResult{
+		Success: strings.HasSuffix(got.(string), expected.(string)),
+		Diff:    "suffix does not match",
+	}
+}
+
+func Test_ValidateExpectedOut_ValidateCustomAssertion(t *testing.T) {
+	t.Cleanup(matcher.RegisterAssertion(matcher.Assertion{Key: "test-ends-with", Parse: matcher.ParseString, Matcher: suffixMatcher{}}))
+
+	r := validateExpectedOut("hello world", ExpectedOut{Custom: map[string]interface{}{"test-ends-with": "world"}})
+	assert.Empty(t, r)
+
+	r = validateExpectedOut("hello world", ExpectedOut{Custom: map[string]interface{}{"test-ends-with": "hello"}})
+	assert.Len(t, r, 1)
+	assert.Equal(t, "suffix does not match", r[0].Diff)
+}
+
+func Test_ValidateExpectedOut_RawOutput(t *testing.T) {
+	matcher.ReadFile = func(filename string) ([]byte, error) {
+		return []byte("  indented\r\n"), nil
+	}
+	defer func() { matcher.ReadFile = os.ReadFile }()
+
+	r := validateExpectedOut("  indented\r\n", ExpectedOut{RawOutput: true, File: "fake.txt", Exactly: "  indented\r\n"})
+	assert.Empty(t, r)
+
+	r = validateExpectedOut("  indented\n", ExpectedOut{RawOutput: true, File: "fake.txt"})
+	assert.Len(t, r, 1)
+}
+
+func Test_processOutput(t *testing.T) {
+	assert.Equal(t, "hello\nworld", processOutput("  hello\r\nworld\n", false))
+	assert.Equal(t, "  hello\r\nworld\n", processOutput("  hello\r\nworld\n", true))
+}
@@ -0,0 +1,146 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// Format is the file format of a suite
+type Format string
+
+// Supported suite formats, all formats except yaml are converted to yaml and share its parser and validation
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+	// FormatMarkdown executes the commands of the console blocks of a markdown document, see parseMarkdown
+	FormatMarkdown Format = "markdown"
+	// FormatTranscript executes the commands of a cram style transcript, see parseTranscript
+	FormatTranscript Format = "transcript"
+)
+
+// ParseFormat returns the format with the given name
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	case "toml":
+		return FormatTOML, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "transcript", "cram", "t":
+		return FormatTranscript, nil
+	}
+	return "", fmt.Errorf("unknown format '%s', expected yaml, json, toml, markdown or transcript", name)
+}
+
+// FormatFromPath returns the format of the file by its extension, yaml is used for all other extensions
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".t":
+		return FormatTranscript
+	}
+	return FormatYAML
+}
+
+// ParseJSON parses the Suite from a json byte slice
+func ParseJSON(content []byte, fileName string) Suite {
+	return Parse(content, FormatJSON, fileName)
+}
+
+// ParseTOML parses the Suite from a toml byte slice
+func ParseTOML(content []byte, fileName string) Suite {
+	return Parse(content, FormatTOML, fileName)
+}
+
+// ParseMarkdown parses the Suite from the console blocks of a markdown document
+func ParseMarkdown(content []byte, fileName string) Suite {
+	return Parse(content, FormatMarkdown, fileName)
+}
+
+// Parse parses the Suite from a byte slice in the given format
+func Parse(content []byte, format Format, fileName string) Suite {
+	return parseSuite(content, format, "", fileName)
+}
+
+// toYAML converts the suite to yaml, the keys and values are kept as they are
+func toYAML(content []byte, format Format) []byte {
+	if format == FormatYAML || len(bytes.TrimSpace(content)) == 0 {
+		return content
+	}
+
+	var value interface{}
+	switch format {
+	case FormatJSON:
+		value = decodeJSON(content)
+	case FormatTOML:
+		var m map[string]interface{}
+		if _, err := toml.Decode(string(content), &m); err != nil {
+			panic(fmt.Sprintf("Failed to parse toml: %s", err))
+		}
+		value = m
+	case FormatMarkdown:
+		value = parseMarkdown(content)
+	case FormatTranscript:
+		value = parseTranscript(content)
+	default:
+		panic(fmt.Sprintf("Failed to parse suite, unknown format '%s'", format))
+	}
+
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %s", format, err))
+	}
+	return out
+}
+
+func decodeJSON(content []byte) interface{} {
+	d := json.NewDecoder(bytes.NewReader(content))
+	// numbers are decoded as integers if possible, i.e. for exit-code and line-count
+	d.UseNumber()
+
+	var value interface{}
+	if err := d.Decode(&value); err != nil {
+		panic(fmt.Sprintf("Failed to parse json: %s", err))
+	}
+	if _, err := d.Token(); err != io.EOF {
+		panic("Failed to parse json: invalid content after the top-level value")
+	}
+
+	return convertJSONNumbers(value)
+}
+
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = convertJSONNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = convertJSONNumbers(e)
+		}
+	}
+	return value
+}
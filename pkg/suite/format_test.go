@@ -0,0 +1,161 @@
+package suite
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const formatYAMLSuite = `
+config:
+  env:
+    KEY: value
+  timeout: 5s
+tests:
+  echo hello:
+    exit-code: 0
+    stdout:
+      contains:
+        - hello
+        - text: l
+          count: 2
+      line-count: 1
+  it should fail:
+    command: exit 1
+    exit-code: [1, 2]
+    skip: true
+`
+
+func Test_ParseJSON(t *testing.T) {
+	json := []byte(`{
+	"config": {"env": {"KEY": "value"}, "timeout": "5s"},
+	"tests": {
+		"echo hello": {
+			"exit-code": 0,
+			"stdout": {"contains": ["hello", {"text": "l", "count": 2}], "line-count": 1}
+		},
+		"it should fail": {"command": "exit 1", "exit-code": [1, 2], "skip": true}
+	}
+}`)
+
+	assert.Equal(t, sortedSuite(ParseYAML([]byte(formatYAMLSuite), "suite")), sortedSuite(ParseJSON(json, "suite")))
+}
+
+func Test_ParseTOML(t *testing.T) {
+	toml := []byte(`
+[config]
+timeout = "5s"
+env = { KEY = "value" }
+
+[tests."echo hello"]
+exit-code = 0
+
+[tests."echo hello".stdout]
+contains = ["hello", { text = "l", count = 2 }]
+line-count = 1
+
+[tests."it should fail"]
+command = "exit 1"
+exit-code = [1, 2]
+skip = true
+`)
+
+	assert.Equal(t, sortedSuite(ParseYAML([]byte(formatYAMLSuite), "suite")), sortedSuite(ParseTOML(toml, "suite")))
+}
+
+func Test_Parse_Lines(t *testing.T) {
+	json := []byte(`{"tests": {"printf 'a\\nb'": {"stdout": {"lines": {"1": "a", "2": "b"}}}}}`)
+	toml := []byte(`
+[tests."printf 'a\\nb'".stdout.lines]
+1 = "a"
+2 = "b"
+`)
+	expected := map[int]string{1: "a", 2: "b"}
+
+	assert.Equal(t, expected, ParseJSON(json, "").GetTests()[0].Expected.Stdout.Lines)
+	assert.Equal(t, expected, ParseTOML(toml, "").GetTests()[0].Expected.Stdout.Lines)
+}
+
+func Test_ParseJSON_SharesValidation(t *testing.T) {
+	var yamlErr, jsonErr interface{}
+	func() {
+		defer func() { yamlErr = recover() }()
+		ParseYAML([]byte("tests:\n  echo hello:\n    stdot: hello\n"), "")
+	}()
+	func() {
+		defer func() { jsonErr = recover() }()
+		ParseJSON([]byte(`{"tests": {"echo hello": {"stdot": "hello"}}}`), "")
+	}()
+
+	assert.NotNil(t, yamlErr)
+	assert.Equal(t, yamlErr, jsonErr)
+}
+
+func Test_Parse_InvalidContent(t *testing.T) {
+	assert.Panics(t, func() {
+		ParseJSON([]byte(`{"tests": {"echo hello": }}`), "")
+	})
+	assert.PanicsWithValue(t, "Failed to parse json: invalid content after the top-level value", func() {
+		ParseJSON([]byte(`{"tests": {}} {}`), "")
+	})
+	assert.Panics(t, func() {
+		ParseTOML([]byte(`tests = [`), "")
+	})
+}
+
+func Test_Parse_EmptyContent(t *testing.T) {
+	assert.Empty(t, ParseJSON([]byte(" \n"), "").GetTests())
+	assert.Empty(t, ParseTOML([]byte(""), "").GetTests())
+}
+
+func Test_Parse_IncludesOtherFormats(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": `
+include: [shared.json, shared.toml]
+tests:
+  echo main:
+    exit-code: 0
+`,
+		"shared.json": `{"config": {"env": {"FROM": "json"}}, "tests": {"echo json": {"exit-code": 1}}}`,
+		"shared.toml": `
+[tests."echo toml"]
+exit-code = 2
+`,
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	test, err := s.GetTestByTitle("echo json")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, test.Expected.ExitCode)
+	assert.Equal(t, "json", test.Command.Env["FROM"])
+
+	test, err = s.GetTestByTitle("echo toml")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, test.Expected.ExitCode)
+}
+
+func Test_FormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatJSON, FormatFromPath("dir/suite.json"))
+	assert.Equal(t, FormatTOML, FormatFromPath("suite.TOML"))
+	assert.Equal(t, FormatYAML, FormatFromPath("suite.yml"))
+	assert.Equal(t, FormatYAML, FormatFromPath("-"))
+}
+
+func Test_ParseFormat(t *testing.T) {
+	f, err := ParseFormat("JSON")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	_, err = ParseFormat("xml")
+	assert.Equal(t, "unknown format 'xml', expected yaml, json, toml, markdown or transcript", err.Error())
+}
+
+// sortedSuite sorts the tests by title, the order of the parsed tests is random
+func sortedSuite(s Suite) Suite {
+	sort.Slice(s.TestCases, func(i, j int) bool {
+		return s.TestCases[i].Title < s.TestCases[j].Title
+	})
+	return s
+}
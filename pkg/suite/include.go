@@ -0,0 +1,366 @@
+package suite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// includeLoader loads a suite and merges the tests, nodes and config of all included suites.
+// Each suite is loaded and merged once, its templates are available in every suite which includes it.
+// Cycles and duplicate test titles cause a panic with the file and line of the definition.
+type includeLoader struct {
+	// loaded suites by their absolute path
+	loaded map[string]includedSuite
+	// absolute paths of the suites whose tests were merged
+	visited map[string]bool
+	// absolute paths of the suites which are currently loaded, used to detect cycles
+	stack []string
+}
+
+func newIncludeLoader() *includeLoader {
+	return &includeLoader{loaded: make(map[string]includedSuite), visited: make(map[string]bool)}
+}
+
+// locatedError is the panic value of errors which already contain the file and line of the definition
+type locatedError string
+
+// loadSuite parses the suite and merges its includes, name is used in error messages if path is empty.
+// The format of included suites is detected by their extension.
+func loadSuite(content []byte, format Format, path string, name string) YAMLSuiteConf {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(locatedError); ok {
+				panic(string(e))
+			}
+			panic(r)
+		}
+	}()
+
+	return newIncludeLoader().load(content, format, path, name)
+}
+
+func (l *includeLoader) load(content []byte, format Format, path string, name string) YAMLSuiteConf {
+	content = toYAML(content, format)
+
+	// includes are loaded before the suite, templates of included suites can be extended by its tests
+	var head struct {
+		Include interface{} `yaml:"include"`
+	}
+	// syntax errors are reported by the strict unmarshal of the suite
+	_ = yaml.Unmarshal(content, &head)
+	includes := toIncludes(head.Include)
+
+	if len(includes) == 0 {
+		return unmarshalSuite(content, nil)
+	}
+
+	label := path
+	if label == "" {
+		label = name
+	}
+
+	dir := "."
+	if path != "" {
+		abs := absPath(path)
+		l.stack = append(l.stack, abs)
+		defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+		dir = filepath.Dir(path)
+	}
+
+	lines := linesOf(content, format)
+	// all included suites provide templates, tests are only merged from suites which were not merged before
+	var withTemplates, included []includedSuite
+	for i, pattern := range includes {
+		at := location(label, lines.include(i))
+
+		for _, file := range resolveInclude(dir, pattern, at) {
+			abs := absPath(file)
+			if i := indexOf(l.stack, abs); i >= 0 {
+				cycle := append(append([]string{}, l.stack[i:]...), abs)
+				panic(locatedError(fmt.Sprintf("%s: include cycle detected: %s", at, strings.Join(cycle, " -> "))))
+			}
+
+			inc, ok := l.loaded[abs]
+			if !ok {
+				conf, includedLines := l.loadFile(file, at)
+				inc = includedSuite{file: file, conf: conf, lines: includedLines}
+				l.loaded[abs] = inc
+			}
+			withTemplates = append(withTemplates, inc)
+
+			if l.visited[abs] {
+				continue
+			}
+			l.visited[abs] = true
+			included = append(included, inc)
+		}
+	}
+
+	// templates of later includes take precedence
+	templates := make(map[string]map[interface{}]interface{})
+	for _, inc := range withTemplates {
+		for name, t := range inc.conf.templates {
+			templates[name] = t
+		}
+	}
+
+	conf := unmarshalSuite(content, templates)
+
+	locations := make(map[string]string)
+	for title := range conf.Tests {
+		locations[title] = location(label, lines.tests[title])
+	}
+
+	for _, inc := range included {
+		for _, title := range sortedTitles(inc.conf.Tests) {
+			loc := location(inc.file, inc.lines.tests[title])
+			if existing, ok := locations[title]; ok {
+				panic(locatedError(fmt.Sprintf("%s: duplicate test '%s', already defined in %s", loc, title, existing)))
+			}
+			locations[title] = loc
+
+			// the tags of the included suite are kept, the tags of the including suite are added to all tests
+			test := inc.conf.Tests[title]
+			test.Tags = mergeTags(inc.conf.Tags, test.Tags)
+			conf.Tests[title] = test
+		}
+
+		mergeIncludedNodes(&conf, inc.conf)
+		mergeIncludedConfig(&conf.Config, inc.conf.Config)
+	}
+
+	return conf
+}
+
+// includedSuite is a loaded suite which is merged into the including suite
+type includedSuite struct {
+	file  string
+	conf  YAMLSuiteConf
+	lines suiteLines
+}
+
+// unmarshalSuite parses the suite, templates are the templates of included suites which can be extended by the tests
+func unmarshalSuite(content []byte, templates map[string]map[interface{}]interface{}) YAMLSuiteConf {
+	conf := YAMLSuiteConf{templates: templates}
+	if err := yaml.UnmarshalStrict(content, &conf); err != nil {
+		panic(err.Error())
+	}
+
+	if conf.Tests == nil {
+		conf.Tests = make(map[string]YAMLTest)
+	}
+	return conf
+}
+
+// loadFile loads an included suite and the line numbers of its tests, errors of the suite are prefixed with its path
+func (l *includeLoader) loadFile(file string, at string) (YAMLSuiteConf, suiteLines) {
+	content := mustReadFile(file, at)
+
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(locatedError); ok {
+				panic(r)
+			}
+			panic(locatedError(fmt.Sprintf("%s: %v", file, r)))
+		}
+	}()
+
+	format := FormatFromPath(file)
+	return l.load(content, format, file, file), linesOf(content, format)
+}
+
+// resolveInclude returns the files matched by the path or glob, sorted by name
+func resolveInclude(dir string, pattern string, at string) []string {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		panic(locatedError(fmt.Sprintf("%s: invalid include '%s': %s", at, pattern, err)))
+	}
+	if len(files) == 0 {
+		panic(locatedError(fmt.Sprintf("%s: include '%s' did not match any file", at, pattern)))
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// mergeIncludedNodes adds the nodes of the included suite, nodes of the including suite take precedence
+func mergeIncludedNodes(conf *YAMLSuiteConf, included YAMLSuiteConf) {
+	for name, node := range included.Nodes {
+		if conf.Nodes == nil {
+			conf.Nodes = make(map[string]YAMLNodeConf)
+		}
+		if _, ok := conf.Nodes[name]; !ok {
+			conf.Nodes[name] = node
+		}
+	}
+}
+
+// mergeIncludedConfig adds the config of the included suite, the config of the including suite takes precedence
+func mergeIncludedConfig(config *YAMLTestConfigConf, included YAMLTestConfigConf) {
+	if len(included.Env) > 0 {
+		env := make(map[string]string)
+		for k, v := range included.Env {
+			env[k] = v
+		}
+		for k, v := range config.Env {
+			env[k] = v
+		}
+		config.Env = env
+	}
+
+	if config.Dir == "" {
+		config.Dir = included.Dir
+	}
+
+	if config.Timeout == "" {
+		config.Timeout = included.Timeout
+	}
+
+	if config.Retries == 0 {
+		config.Retries = included.Retries
+	}
+
+	if config.Interval == "" {
+		config.Interval = included.Interval
+	}
+
+	if config.MaxOutput == "" {
+		config.MaxOutput = included.MaxOutput
+	}
+
+	if !config.InheritEnv {
+		config.InheritEnv = included.InheritEnv
+	}
+
+	if len(config.Nodes) == 0 {
+		config.Nodes = included.Nodes
+	}
+}
+
+// toIncludes converts the include value to a list of paths
+func toIncludes(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var includes []string
+		for _, i := range v {
+			s, ok := i.(string)
+			if !ok {
+				panic(fmt.Sprintf("Failed to parse include, expected a path, got %v", i))
+			}
+			includes = append(includes, s)
+		}
+		return includes
+	default:
+		panic(fmt.Sprintf("Failed to parse include, expected a path or a list of paths, got %v", value))
+	}
+}
+
+// suiteLines holds the line numbers of the test titles and includes of a suite
+type suiteLines struct {
+	tests    map[string]int
+	includes []int
+}
+
+func (l suiteLines) include(i int) int {
+	if i < len(l.includes) {
+		return l.includes[i]
+	}
+	return 0
+}
+
+// linesOf returns the lines of the converted yaml content, lines of other formats are unknown
+func linesOf(content []byte, format Format) suiteLines {
+	if format != FormatYAML {
+		return suiteLines{tests: make(map[string]int)}
+	}
+	return parseLines(content)
+}
+
+// parseLines reads the line numbers from the yaml document, unknown lines are 0
+func parseLines(content []byte) suiteLines {
+	lines := suiteLines{tests: make(map[string]int)}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+		return lines
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yamlv3.MappingNode {
+		return lines
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "tests":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				lines.tests[value.Content[j].Value] = value.Content[j].Line
+			}
+		case "include":
+			if value.Kind == yamlv3.ScalarNode {
+				lines.includes = append(lines.includes, value.Line)
+			}
+			for _, n := range value.Content {
+				lines.includes = append(lines.includes, n.Line)
+			}
+		}
+	}
+
+	return lines
+}
+
+func location(file string, line int) string {
+	if line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func mustReadFile(file string, at string) []byte {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		panic(locatedError(fmt.Sprintf("%s: could not read include: %s", at, err)))
+	}
+	return content
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+func indexOf(values []string, s string) int {
+	for i, v := range values {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func sortedTitles(tests map[string]YAMLTest) []string {
+	var titles []string
+	for t := range tests {
+		titles = append(titles, t)
+	}
+	sort.Strings(titles)
+	return titles
+}
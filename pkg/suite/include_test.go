@@ -0,0 +1,224 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSuites(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	return dir
+}
+
+func loadTestSuite(t *testing.T, dir string, name string) Suite {
+	p := filepath.Join(dir, name)
+	content, err := os.ReadFile(p)
+	assert.Nil(t, err)
+	return NewSuiteFromFile(p, content, []byte{}, name)
+}
+
+func Test_Include_MergesTestsNodesAndConfig(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": `
+include: shared/common.yaml
+config:
+  env:
+    NAME: main
+  timeout: 5s
+nodes:
+  host:
+    type: local
+tests:
+  echo main:
+    exit-code: 0
+`,
+		"shared/common.yaml": `
+config:
+  env:
+    NAME: shared
+    SHARED: "true"
+  timeout: 10s
+  retries: 2
+nodes:
+  host:
+    type: ssh
+    addr: localhost
+  docker-host:
+    type: docker
+    image: alpine
+tests:
+  echo shared:
+    exit-code: 0
+`,
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	assert.Len(t, s.GetTests(), 2)
+	test, err := s.GetTestByTitle("echo shared")
+	assert.Nil(t, err)
+	assert.Equal(t, "main.yaml", test.FileName)
+	assert.Equal(t, map[string]string{"NAME": "main", "SHARED": "true"}, test.Command.Env)
+	assert.Equal(t, "5s", test.Command.Timeout)
+	assert.Equal(t, 2, test.Command.Retries)
+
+	host, _ := s.GetNodeByName("host")
+	assert.Equal(t, "local", host.Type)
+	_, err = s.GetNodeByName("docker-host")
+	assert.Nil(t, err)
+}
+
+func Test_Include_Globs(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": `
+include:
+  - helpers/*.yaml
+tests:
+  echo main:
+    exit-code: 0
+`,
+		"helpers/a.yaml": "tests:\n  echo a:\n    exit-code: 0\n",
+		"helpers/b.yaml": "tests:\n  echo b:\n    exit-code: 0\n",
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	var titles []string
+	for _, test := range s.GetTests() {
+		titles = append(titles, test.Title)
+	}
+	assert.ElementsMatch(t, []string{"echo main", "echo a", "echo b"}, titles)
+}
+
+func Test_Include_Nested(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml":       "include: a/a.yaml\ntests: {}\n",
+		"a/a.yaml":        "include: ../b/b.yaml\ntests:\n  echo a:\n    exit-code: 0\n",
+		"b/b.yaml":        "include: common.yaml\ntests:\n  echo b:\n    exit-code: 0\n",
+		"b/common.yaml":   "tests:\n  echo common:\n    exit-code: 0\n",
+		"unused/b/b.yaml": "tests: {}\n",
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	assert.Len(t, s.GetTests(), 3)
+}
+
+func Test_Include_Tags(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml":   "include: common.yaml\ntags: [main]\ntests:\n  echo main:\n    tags: [fast]\n",
+		"common.yaml": "tags: [common]\ntests:\n  echo common:\n    tags: [slow]\n",
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	main, _ := s.GetTestByTitle("echo main")
+	assert.Equal(t, []string{"main", "fast"}, main.Tags)
+	common, _ := s.GetTestByTitle("echo common")
+	assert.Equal(t, []string{"main", "common", "slow"}, common.Tags)
+}
+
+func Test_Include_SameFileTwice(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml":   "include: [a.yaml, b.yaml]\ntests: {}\n",
+		"a.yaml":      "include: common.yaml\ntests:\n  echo a:\n    exit-code: 0\n",
+		"b.yaml":      "include: common.yaml\ntests:\n  echo b:\n    exit-code: 0\n",
+		"common.yaml": "tests:\n  echo common:\n    exit-code: 0\n",
+	})
+
+	s := loadTestSuite(t, dir, "main.yaml")
+
+	assert.Len(t, s.GetTests(), 3)
+}
+
+func Test_Include_DiamondSharesTemplates(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"a.yaml":     "include: [inc/b.yaml, inc/c.yaml]\ntests: {}\n",
+		"inc/b.yaml": "include: d.yaml\ntests:\n  b test:\n    extends: base\n",
+		"inc/c.yaml": "include: d.yaml\ntests:\n  c test:\n    extends: base\n",
+		"inc/d.yaml": "templates:\n  base:\n    command: echo base\n    stdout: base\ntests:\n  echo d:\n    exit-code: 0\n",
+	})
+
+	s := loadTestSuite(t, dir, "a.yaml")
+
+	assert.Len(t, s.GetTests(), 3)
+	for _, title := range []string{"b test", "c test"} {
+		test, err := s.GetTestByTitle(title)
+		assert.Nil(t, err)
+		assert.Equal(t, "echo base", test.Command.Cmd)
+	}
+}
+
+func Test_Include_Cycle(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": "include: a.yaml\ntests: {}\n",
+		"a.yaml":    "tests: {}\ninclude: b.yaml\n",
+		"b.yaml":    "include:\n  - a.yaml\n",
+	})
+
+	a := filepath.Join(dir, "a.yaml")
+	b := filepath.Join(dir, "b.yaml")
+	assert.PanicsWithValue(t, b+":2: include cycle detected: "+a+" -> "+b+" -> "+a, func() {
+		loadTestSuite(t, dir, "main.yaml")
+	})
+}
+
+func Test_Include_DuplicateTitle(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": `include: common.yaml
+tests:
+  echo hello:
+    exit-code: 0
+`,
+		"common.yaml": `tests:
+  echo bye:
+    exit-code: 0
+  echo hello:
+    exit-code: 0
+`,
+	})
+
+	main := filepath.Join(dir, "main.yaml")
+	common := filepath.Join(dir, "common.yaml")
+	assert.PanicsWithValue(t, common+":4: duplicate test 'echo hello', already defined in "+main+":3", func() {
+		loadTestSuite(t, dir, "main.yaml")
+	})
+}
+
+func Test_Include_NoMatch(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml":   "tests: {}\ninclude:\n  - common.yaml\n  - missing/*.yaml\n",
+		"common.yaml": "tests: {}\n",
+	})
+
+	main := filepath.Join(dir, "main.yaml")
+	assert.PanicsWithValue(t, main+":4: include '"+filepath.Join(dir, "missing/*.yaml")+"' did not match any file", func() {
+		loadTestSuite(t, dir, "main.yaml")
+	})
+}
+
+func Test_Include_InvalidIncludedSuite(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml":   "include: common.yaml\ntests: {}\n",
+		"common.yaml": "tests:\n  echo hello:\n    stdout:\n      unknown: value\n",
+	})
+
+	common := filepath.Join(dir, "common.yaml")
+	assert.PanicsWithValue(t, common+": Key unknown is not allowed.", func() {
+		loadTestSuite(t, dir, "main.yaml")
+	})
+}
+
+func Test_Include_InvalidValue(t *testing.T) {
+	assert.PanicsWithValue(t, "Failed to parse include, expected a path or a list of paths, got map[a:b]", func() {
+		ParseYAML([]byte("include:\n  a: b\ntests: {}\n"), "")
+	})
+}
@@ -0,0 +1,729 @@
+package suite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+)
+
+// ValidationError is an invalid definition of a suite, Line and Column are 0 if the position is unknown
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	switch {
+	case e.File == "":
+		return e.Message
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// ValidationErrors holds all problems of the linted suites
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	var lines []string
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Linter validates suites without executing them and collects all problems with their positions.
+// Positions are only known for yaml suites, other formats are converted before they are validated.
+// Node references are validated against the nodes of all linted suites,
+// i.e. the nodes of the suite, its includes and the --config file.
+type Linter struct {
+	errors ValidationErrors
+	// nodes holds the names of all defined nodes
+	nodes map[string]bool
+	// references holds the nodes which are used by tests and configs
+	references []nodeReference
+	// visited holds the absolute paths of the linted files to lint every include once
+	visited map[string]bool
+}
+
+type nodeReference struct {
+	name string
+	at   ValidationError
+}
+
+// yamlErrorLine matches the line of yaml syntax errors
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// nodeTypes are the types of nodes which can be executed by the runtime
+var nodeTypes = []string{"local", "ssh", "docker"}
+
+// NewLinter creates a linter
+func NewLinter() *Linter {
+	return &Linter{
+		nodes:   map[string]bool{"local": true},
+		visited: make(map[string]bool),
+	}
+}
+
+// Lint validates the suite, the arguments are the same as for NewSuiteFromFileWithFormat.
+// Problems are reported with the path of the suite, or with name if path is empty.
+func (l *Linter) Lint(content []byte, format Format, path string, name string) {
+	file := path
+	if file == "" {
+		file = name
+	}
+	if path != "" {
+		l.visited[absPath(path)] = true
+	}
+
+	before := len(l.errors)
+	l.lintFile(content, format, path, file)
+
+	// includes, templates and all other definitions which depend on other suites are validated by the parser
+	if len(l.errors) == before {
+		l.parse(content, format, path, file)
+	}
+}
+
+// Errors returns all problems of the linted suites sorted by file and position
+func (l *Linter) Errors() ValidationErrors {
+	errors := append(ValidationErrors{}, l.errors...)
+	for _, r := range l.references {
+		if !l.nodes[r.name] {
+			r.at.Message = fmt.Sprintf("unknown node '%s', expected one of: %s", r.name, strings.Join(l.nodeNames(), ", "))
+			errors = append(errors, r.at)
+		}
+	}
+
+	errors = unique(errors)
+	sort.SliceStable(errors, func(i, j int) bool {
+		if errors[i].File != errors[j].File {
+			return errors[i].File < errors[j].File
+		}
+		if errors[i].Line != errors[j].Line {
+			return errors[i].Line < errors[j].Line
+		}
+		return errors[i].Column < errors[j].Column
+	})
+	return errors
+}
+
+// unique removes duplicate problems, i.e. of maps which are merged into other maps
+func unique(errors ValidationErrors) ValidationErrors {
+	var result ValidationErrors
+	seen := make(map[ValidationError]bool)
+	for _, e := range errors {
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (l *Linter) nodeNames() []string {
+	var names []string
+	for n := range l.nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lintFile validates the structure of the suite and of all suites it includes
+func (l *Linter) lintFile(content []byte, format Format, path string, file string) {
+	d := &document{linter: l, file: file, positions: format == FormatYAML}
+
+	defer func() {
+		// the conversion of other formats panics on syntax errors
+		if r := recover(); r != nil {
+			d.errorf(nil, "%v", r)
+		}
+	}()
+
+	content = toYAML(content, format)
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		d.syntaxError(err)
+		return
+	}
+	if len(doc.Content) == 0 {
+		return
+	}
+
+	includes := d.lintSuite(doc.Content[0])
+
+	dir := "."
+	if path != "" {
+		dir = filepath.Dir(path)
+	}
+	for _, pattern := range includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		// invalid and missing includes are reported by the parser
+		files, _ := filepath.Glob(pattern)
+		for _, f := range files {
+			if l.visited[absPath(f)] {
+				continue
+			}
+			l.visited[absPath(f)] = true
+
+			if content, err := os.ReadFile(f); err == nil {
+				l.lintFile(content, FormatFromPath(f), f, f)
+			}
+		}
+	}
+}
+
+// parse parses the suite and reports the panic of the parser
+func (l *Linter) parse(content []byte, format Format, path string, file string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(locatedError); ok {
+				l.errors = append(l.errors, ValidationError{Message: string(e)})
+				return
+			}
+			l.errors = append(l.errors, ValidationError{File: file, Message: fmt.Sprint(r)})
+		}
+	}()
+
+	loader := newIncludeLoader()
+	conf := loader.load(content, format, path, file)
+	convertYAMLSuiteConfToTestCases(conf, file)
+}
+
+// document validates a single suite
+type document struct {
+	linter    *Linter
+	file      string
+	positions bool
+}
+
+func (d *document) at(n *yamlv3.Node) ValidationError {
+	e := ValidationError{File: d.file}
+	if n != nil && d.positions {
+		e.Line = n.Line
+		e.Column = n.Column
+	}
+	return e
+}
+
+func (d *document) errorf(n *yamlv3.Node, format string, args ...interface{}) {
+	e := d.at(n)
+	e.Message = fmt.Sprintf(format, args...)
+	d.linter.errors = append(d.linter.errors, e)
+}
+
+func (d *document) syntaxError(err error) {
+	e := d.at(nil)
+	e.Message = err.Error()
+	if m := yamlErrorLine.FindStringSubmatch(e.Message); m != nil && d.positions {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Message = m[2]
+	}
+	d.linter.errors = append(d.linter.errors, e)
+}
+
+// lintSuite validates the root of the suite and returns its includes
+func (d *document) lintSuite(n *yamlv3.Node) []string {
+	n = resolve(n)
+	if isNull(n) {
+		return nil
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a map of tests, config, nodes, include, tags and templates, got %s", describe(n))
+		return nil
+	}
+
+	var includes []string
+	for _, p := range d.pairs(n, suiteKeys) {
+		switch p.key.Value {
+		case "tests", "templates":
+			d.lintMap(p.value, d.lintTest)
+		case "config":
+			d.lintConfig(p.value)
+		case "nodes":
+			d.lintMap(p.value, func(name *yamlv3.Node, node *yamlv3.Node) {
+				d.linter.nodes[name.Value] = true
+				d.lintNode(node)
+			})
+		case "include":
+			includes = d.lintStrings(p.value)
+		case "tags":
+			d.lintTags(p.value)
+		}
+	}
+	return includes
+}
+
+// lintMap validates the values of a map with lint, null values are allowed
+func (d *document) lintMap(n *yamlv3.Node, lint func(key *yamlv3.Node, value *yamlv3.Node)) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a map, got %s", describe(n))
+		return
+	}
+	for _, p := range d.pairs(n, nil) {
+		lint(p.key, p.value)
+	}
+}
+
+func (d *document) lintTest(_ *yamlv3.Node, n *yamlv3.Node) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a test, got %s", describe(n))
+		return
+	}
+
+	pairs := d.pairs(n, yamlKeys(reflect.TypeOf(YAMLTest{})))
+
+	// binary assertions require the raw output of all streams, see YAMLSuiteConf.UnmarshalYAML
+	raw := false
+	for _, p := range pairs {
+		switch p.key.Value {
+		case "raw-output":
+			var b bool
+			raw = raw || isScalarOf(resolve(p.value), &b) && b
+		case "stdout", "stderr", "output":
+			raw = raw || usesBinaryAssertion(decode(p.value))
+		}
+	}
+
+	for _, p := range pairs {
+		switch p.key.Value {
+		case "exit-code":
+			d.lintParser(p.value, func(v interface{}) { toExitCodeExpectation(v) })
+		case "stdout", "stderr", "output":
+			d.lintExpectedOut(p.value, raw)
+		case "extends", "validate":
+			d.lintStrings(p.value)
+		case "config":
+			d.lintConfig(p.value)
+		case "tags":
+			d.lintTags(p.value)
+		case "run-if", "skip-if":
+			d.lintCondition(p.value)
+		default:
+			d.lintType(p.value, yamlField(reflect.TypeOf(YAMLTest{}), p.key.Value).Type)
+		}
+	}
+}
+
+// lintExpectedOut validates stdout, stderr and output with the parsers of the assertions
+func (d *document) lintExpectedOut(n *yamlv3.Node, raw bool) {
+	n = resolve(n)
+	if isNull(n) || n.Kind == yamlv3.ScalarNode {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a text or a map of assertions, got %s", describe(n))
+		return
+	}
+
+	for _, p := range d.pairs(n, nil) {
+		key := p.key.Value
+		switch key {
+		case "normalize":
+			d.lintParser(p.value, func(v interface{}) { toNormalizers(v) })
+			continue
+		case "message":
+			d.lintType(p.value, reflect.TypeOf(""))
+			continue
+		case "messages":
+			d.lintParser(p.value, func(v interface{}) { toMessages(v) })
+			continue
+		}
+
+		a, ok := matcher.GetAssertion(key)
+		if !ok {
+			d.errorf(p.key, "unknown assertion '%s'", key)
+			continue
+		}
+
+		v := decode(p.value)
+		if v == nil {
+			continue
+		}
+
+		parse := a.Parse
+		if raw && a.RawParse != nil {
+			parse = a.RawParse
+		}
+		if _, err := parse(v); err != nil {
+			var unknown matcher.UnknownKeyError
+			if errors.As(err, &unknown) {
+				d.errorf(findKey(p.value, unknown.Key), "unknown key '%s' in %s", unknown.Key, key)
+				continue
+			}
+			d.errorf(p.value, "invalid %s: %s", key, err)
+		}
+	}
+}
+
+// findKey returns the first key with the given name in the maps of n, or n if it is not found
+func findKey(n *yamlv3.Node, key string) *yamlv3.Node {
+	var find func(n *yamlv3.Node) *yamlv3.Node
+	find = func(n *yamlv3.Node) *yamlv3.Node {
+		n = resolve(n)
+		for i, c := range n.Content {
+			if n.Kind == yamlv3.MappingNode && i%2 == 0 && c.Value == key {
+				return c
+			}
+			if found := find(c); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	if found := find(n); found != nil {
+		return found
+	}
+	return n
+}
+
+// lintParser reports the panic of the parser of the value
+func (d *document) lintParser(n *yamlv3.Node, parse func(v interface{})) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.errorf(n, "%v", r)
+		}
+	}()
+	parse(decode(n))
+}
+
+func (d *document) lintConfig(n *yamlv3.Node) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a config, got %s", describe(n))
+		return
+	}
+
+	t := reflect.TypeOf(YAMLTestConfigConf{})
+	for _, p := range d.pairs(n, yamlKeys(t)) {
+		v := resolve(p.value)
+		if !d.lintType(v, yamlField(t, p.key.Value).Type) || isNull(v) {
+			continue
+		}
+
+		switch p.key.Value {
+		case "timeout", "interval":
+			if _, err := time.ParseDuration(v.Value); err != nil {
+				d.errorf(v, "invalid %s '%s', expected a duration like 500ms, 10s or 1m", p.key.Value, v.Value)
+			}
+		case "max-output":
+			if err := runtime.ValidateMaxOutput(v.Value); err != nil {
+				d.errorf(v, "%s", err)
+			}
+		case "nodes":
+			for _, name := range v.Content {
+				name = resolve(name)
+				d.linter.references = append(d.linter.references, nodeReference{name: name.Value, at: d.at(name)})
+			}
+		}
+	}
+}
+
+func (d *document) lintNode(n *yamlv3.Node) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a node, got %s", describe(n))
+		return
+	}
+
+	t := reflect.TypeOf(YAMLNodeConf{})
+	for _, p := range d.pairs(n, yamlKeys(t)) {
+		v := resolve(p.value)
+		if !d.lintType(v, yamlField(t, p.key.Value).Type) {
+			continue
+		}
+
+		if p.key.Value == "type" && !isNull(v) && indexOf(nodeTypes, v.Value) < 0 {
+			d.errorf(v, "unknown node type '%s', expected one of: %s", v.Value, strings.Join(nodeTypes, ", "))
+		}
+	}
+}
+
+// lintTags validates the tags can be used in tag expressions
+func (d *document) lintTags(n *yamlv3.Node) {
+	if !d.lintType(n, reflect.TypeOf([]string{})) || isNull(resolve(n)) {
+		return
+	}
+	for _, t := range resolve(n).Content {
+		t = resolve(t)
+		if err := runtime.ValidateTag(t.Value); err != nil {
+			d.errorf(t, "%s", err)
+		}
+	}
+}
+
+// lintCondition validates run-if and skip-if
+func (d *document) lintCondition(n *yamlv3.Node) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yamlv3.MappingNode {
+		d.errorf(n, "expected a map of %s, got %s", strings.Join(yamlKeys(reflect.TypeOf(YAMLCondition{})), ", "), describe(n))
+		return
+	}
+
+	for _, p := range d.pairs(n, yamlKeys(reflect.TypeOf(YAMLCondition{}))) {
+		d.lintStrings(p.value)
+	}
+}
+
+// lintStrings validates a text or a list of texts and returns them
+func (d *document) lintStrings(n *yamlv3.Node) []string {
+	n = resolve(n)
+	switch {
+	case isNull(n):
+		return nil
+	case n.Kind == yamlv3.ScalarNode:
+		return []string{n.Value}
+	case n.Kind != yamlv3.SequenceNode:
+		d.errorf(n, "expected a text or a list of texts, got %s", describe(n))
+		return nil
+	}
+
+	var values []string
+	for _, e := range n.Content {
+		e = resolve(e)
+		if e.Kind != yamlv3.ScalarNode {
+			d.errorf(e, "expected a text, got %s", describe(e))
+			continue
+		}
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+// lintType validates the value against the type of the field it is unmarshalled into
+func (d *document) lintType(n *yamlv3.Node, t reflect.Type) bool {
+	n = resolve(n)
+	if isNull(n) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.String:
+		if n.Kind == yamlv3.ScalarNode {
+			return true
+		}
+		d.errorf(n, "expected a text, got %s", describe(n))
+	case reflect.Bool:
+		var b bool
+		if isScalarOf(n, &b) {
+			return true
+		}
+		d.errorf(n, "expected true or false, got %s", describe(n))
+	case reflect.Int:
+		var i int
+		if isScalarOf(n, &i) {
+			return true
+		}
+		d.errorf(n, "expected a number, got %s", describe(n))
+	case reflect.Slice:
+		if n.Kind != yamlv3.SequenceNode {
+			d.errorf(n, "expected a list, got %s", describe(n))
+			return false
+		}
+		valid := true
+		for _, e := range n.Content {
+			valid = d.lintType(e, t.Elem()) && valid
+		}
+		return valid
+	case reflect.Map:
+		if n.Kind != yamlv3.MappingNode {
+			d.errorf(n, "expected a map, got %s", describe(n))
+			return false
+		}
+		valid := true
+		for _, p := range d.pairs(n, nil) {
+			valid = d.lintType(p.value, t.Elem()) && valid
+		}
+		return valid
+	}
+	return false
+}
+
+type pair struct {
+	key   *yamlv3.Node
+	value *yamlv3.Node
+}
+
+// pairs returns the keys and values of the map including merged maps, duplicate keys are reported.
+// If keys is not nil all other keys are reported.
+func (d *document) pairs(n *yamlv3.Node, keys []string) []pair {
+	var pairs []pair
+	var merged []*yamlv3.Node
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := resolve(n.Content[i]), n.Content[i+1]
+		if key.Tag == "!!merge" {
+			merged = append(merged, mergedMaps(value)...)
+			continue
+		}
+
+		if seen[key.Value] {
+			d.errorf(key, "duplicate key '%s'", key.Value)
+			continue
+		}
+		seen[key.Value] = true
+
+		if keys != nil && indexOf(keys, key.Value) < 0 {
+			d.errorf(key, "unknown key '%s', expected one of: %s", key.Value, strings.Join(keys, ", "))
+			continue
+		}
+		pairs = append(pairs, pair{key: key, value: value})
+	}
+
+	// keys of the map take precedence over merged keys
+	for _, m := range merged {
+		if m.Kind != yamlv3.MappingNode {
+			d.errorf(m, "expected a map to merge, got %s", describe(m))
+			continue
+		}
+		for _, p := range d.pairs(m, keys) {
+			if !seen[p.key.Value] {
+				seen[p.key.Value] = true
+				pairs = append(pairs, p)
+			}
+		}
+	}
+	return pairs
+}
+
+func mergedMaps(n *yamlv3.Node) []*yamlv3.Node {
+	n = resolve(n)
+	if n.Kind == yamlv3.SequenceNode {
+		var maps []*yamlv3.Node
+		for _, e := range n.Content {
+			maps = append(maps, resolve(e))
+		}
+		return maps
+	}
+	return []*yamlv3.Node{n}
+}
+
+// yamlKeys returns the sorted yaml keys of the struct fields
+func yamlKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if key := yamlKey(t.Field(i)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func yamlField(t reflect.Type, key string) reflect.StructField {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlKey(t.Field(i)) == key {
+			return t.Field(i)
+		}
+	}
+	panic(fmt.Sprintf("%s has no field with key %s", t, key))
+}
+
+func yamlKey(f reflect.StructField) string {
+	key := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	if key == "-" || f.PkgPath != "" {
+		return ""
+	}
+	return key
+}
+
+func resolve(n *yamlv3.Node) *yamlv3.Node {
+	for n.Kind == yamlv3.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// isScalarOf checks if the value is an unquoted scalar which is unmarshalled into out by yaml.v2,
+// i.e. yes is a boolean in yaml.v2 but not in yaml.v3
+func isScalarOf(n *yamlv3.Node, out interface{}) bool {
+	if n.Kind != yamlv3.ScalarNode || n.Style&(yamlv3.SingleQuotedStyle|yamlv3.DoubleQuotedStyle) != 0 {
+		return false
+	}
+	return yaml.Unmarshal([]byte(n.Value), out) == nil
+}
+
+func isNull(n *yamlv3.Node) bool {
+	return n.Kind == yamlv3.ScalarNode && n.Tag == "!!null"
+}
+
+func describe(n *yamlv3.Node) string {
+	switch n.Kind {
+	case yamlv3.MappingNode:
+		return "a map"
+	case yamlv3.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("'%s'", n.Value)
+}
+
+// decode decodes the value like yaml.v2 to be parsed by the parsers of the suite
+func decode(n *yamlv3.Node) interface{} {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil
+	}
+	return toV2(v)
+}
+
+func toV2(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[interface{}]interface{})
+		for k, e := range v {
+			m[k] = toV2(e)
+		}
+		return m
+	case map[interface{}]interface{}:
+		for k, e := range v {
+			v[k] = toV2(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = toV2(e)
+		}
+	}
+	return value
+}
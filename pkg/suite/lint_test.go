@@ -0,0 +1,192 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lint(content string, format Format) ValidationErrors {
+	l := NewLinter()
+	l.Lint([]byte(content), format, "", "suite.yaml")
+	return l.Errors()
+}
+
+func Test_Lint_Valid(t *testing.T) {
+	content := `
+config:
+  timeout: 5s
+  nodes: [local, docker-host]
+nodes:
+  docker-host:
+    type: docker
+    image: alpine
+templates:
+  base: &base
+    exit-code: 0
+tests:
+  echo hello:
+    <<: *base
+    stdout:
+      contains: [hello]
+      message: greeting
+    skip: yes
+  exit 1:
+    exit-code: [1, 2-5]
+`
+	assert.Empty(t, lint(content, FormatYAML))
+	assert.Empty(t, lint("", FormatYAML))
+}
+
+func Test_Lint_ReportsAllProblems(t *testing.T) {
+	content := `config:
+  timeout: 5 seconds
+  retries: many
+  nodes: [missing]
+nodes:
+  ssh-host:
+    type: telnet
+    port: 22
+tests:
+  echo hello:
+    stdout:
+      contain: hello
+      line-count: two
+    exit-code: abc
+    skip: "true"
+    unknown: value
+  echo bye:
+    command: echo bye
+    command: echo twice
+    stdout:
+      normalize: [unknown]
+    config:
+      interval: soon
+      max-output: lots
+`
+	assert.Equal(t, []string{
+		"suite.yaml:2:12: invalid timeout '5 seconds', expected a duration like 500ms, 10s or 1m",
+		"suite.yaml:3:12: expected a number, got 'many'",
+		"suite.yaml:4:11: unknown node 'missing', expected one of: local, ssh-host",
+		"suite.yaml:7:11: unknown node type 'telnet', expected one of: local, ssh, docker",
+		"suite.yaml:8:5: unknown key 'port', expected one of: addr, docker-exec-user, identity-file, image, pass, privileged, type, user",
+		"suite.yaml:12:7: unknown assertion 'contain'",
+		"suite.yaml:13:19: invalid line-count: expected an int, got two",
+		"suite.yaml:14:16: Invalid exit-code abc, expected a number or a range like 1-125",
+		"suite.yaml:15:11: expected true or false, got 'true'",
+		"suite.yaml:16:5: unknown key 'unknown', expected one of: command, config, description, exit-code, extends, output, raw-output, run-if, skip, skip-if, stderr, stdout, tags, validate",
+		"suite.yaml:19:5: duplicate key 'command'",
+		"suite.yaml:21:18: normalizer unknown does not exist",
+		"suite.yaml:23:17: invalid interval 'soon', expected a duration like 500ms, 10s or 1m",
+		"suite.yaml:24:19: invalid max-output 'lots', expected a size like 512, 64KB or 10MB",
+	}, messages(lint(content, FormatYAML)))
+}
+
+func Test_Lint_SyntaxError(t *testing.T) {
+	assert.Equal(t, []string{"suite.yaml:2: did not find expected node content"}, messages(lint("tests:\n  echo: [\n", FormatYAML)))
+	assert.Equal(t, []string{"suite.yaml: expected a map of tests, config, nodes, include, tags and templates, got a list"}, messages(lint("[]", FormatJSON)))
+	assert.Len(t, lint("{", FormatJSON), 1)
+}
+
+func Test_Lint_PositionsAreOnlyReportedForYAML(t *testing.T) {
+	errs := lint(`{"tests": {"echo hello": {"stdout": {"contain": "hello"}}}}`, FormatJSON)
+
+	assert.Equal(t, []string{"suite.yaml: unknown assertion 'contain'"}, messages(errs))
+}
+
+func Test_Lint_Tags(t *testing.T) {
+	errs := lint("tags: [smoke, a b]\ntests:\n  echo hello:\n    tags: [\"!slow\", 1]\n  echo bye:\n    tags: smoke\n", FormatYAML)
+
+	assert.Equal(t, []string{
+		"suite.yaml:1:15: invalid tag 'a b', tags must not be empty or contain whitespace or any of ()!&|",
+		"suite.yaml:4:12: invalid tag '!slow', tags must not be empty or contain whitespace or any of ()!&|",
+		"suite.yaml:6:11: expected a list, got 'smoke'",
+	}, messages(errs))
+}
+
+func Test_Lint_Conditions(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    run-if:\n      platform: linux\n      binary: [{go: 1}]\n    skip-if: windows\n", FormatYAML)
+
+	assert.Equal(t, []string{
+		"suite.yaml:4:7: unknown key 'platform', expected one of: arch, binary, command, env, os",
+		"suite.yaml:5:16: expected a text, got a map",
+		"suite.yaml:6:14: expected a map of arch, binary, command, env, os, got 'windows'",
+	}, messages(errs))
+}
+
+func Test_Lint_ExitCodeRange(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    exit-code: [0, 10-50foo]\n", FormatYAML)
+
+	assert.Equal(t, []string{"suite.yaml:3:16: Invalid exit-code 10-50foo, expected a number or a range like 1-125"}, messages(errs))
+}
+
+func Test_Lint_EmptyExitCodeList(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    exit-code: []\n", FormatYAML)
+
+	assert.Equal(t, []string{"suite.yaml:3:16: Invalid exit-code [], expected at least one exit code or range, use any to accept all exit codes"}, messages(errs))
+}
+
+func Test_Lint_NormalizeRegex(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    stdout:\n      normalize:\n        - regex: '[0-9'\n", FormatYAML)
+
+	assert.Equal(t, []string{"suite.yaml:5:9: invalid regex in normalize: error parsing regexp: missing closing ]: `[0-9`"}, messages(errs))
+}
+
+func Test_Lint_UnknownKeyInAssertion(t *testing.T) {
+	errs := lint(`tests:
+  echo hello:
+    stdout:
+      table:
+        format: csv
+        cells:
+          - {row: 1, column: a, value: b, bogus: 1}
+      contains:
+        - text: hello
+          counts: 1
+`, FormatYAML)
+
+	assert.Equal(t, []string{
+		"suite.yaml:7:43: unknown key 'bogus' in table",
+		"suite.yaml:10:11: unknown key 'counts' in contains",
+	}, messages(errs))
+}
+
+func Test_Lint_LineNumbers(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    stdout:\n      lines:\n        0: hello\n", FormatYAML)
+
+	assert.Equal(t, []string{"suite.yaml:5:9: invalid lines: line numbers start at 1, got 0"}, messages(errs))
+}
+
+func Test_Lint_ReportsParserErrors(t *testing.T) {
+	errs := lint("tests:\n  echo hello:\n    extends: missing\n", FormatYAML)
+
+	assert.Equal(t, []string{"suite.yaml: Test 'echo hello' extends template 'missing' which does not exist"}, messages(errs))
+}
+
+func Test_Lint_Includes(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "nodes.yaml"), []byte("nodes:\n  remote:\n    type: ssh\n    addr: localhost:22\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("tests:\n  echo:\n    skip: maybe\n"), 0o644))
+
+	l := NewLinter()
+	l.Lint([]byte("include: nodes.yaml\nconfig:\n  nodes: [remote]\n"), FormatYAML, filepath.Join(dir, "suite.yaml"), "")
+	assert.Empty(t, l.Errors())
+
+	l.Lint([]byte("include: [invalid.yaml, missing.yaml]\n"), FormatYAML, filepath.Join(dir, "other.yaml"), "")
+	assert.Equal(t, []string{filepath.Join(dir, "invalid.yaml") + ":3:11: expected true or false, got 'maybe'"}, messages(l.Errors()))
+
+	l = NewLinter()
+	l.Lint([]byte("include: missing.yaml\n"), FormatYAML, filepath.Join(dir, "other.yaml"), "")
+	assert.Len(t, l.Errors(), 1)
+	assert.Contains(t, l.Errors()[0].Error(), "other.yaml:1: include '"+filepath.Join(dir, "missing.yaml")+"' did not match any file")
+}
+
+func messages(errs ValidationErrors) []string {
+	var result []string
+	for _, e := range errs {
+		result = append(result, e.Error())
+	}
+	return result
+}
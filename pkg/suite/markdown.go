@@ -0,0 +1,165 @@
+package suite
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// languages of fenced code blocks which are executed as tests
+var markdownLanguages = []string{"console", "shell-session"}
+
+// prompts of commands and continued lines of commands in console blocks and transcripts
+const (
+	consolePrompt   = "$ "
+	consoleContinue = "> "
+)
+
+var (
+	markdownFence  = regexp.MustCompile("^( *)(`{3,}|~{3,})\\s*(.*)$")
+	exitCodeMarker = regexp.MustCompile(`^\[(\d+)\]$`)
+)
+
+// consoleCommand is a command of a console block or transcript with its expected output
+type consoleCommand struct {
+	line     int
+	command  []string
+	output   []string
+	exitCode int
+	skip     bool
+	// outputStart and outputEnd are the indexes of the output and exit code lines of a transcript, see UpdateTranscript
+	outputStart int
+	outputEnd   int
+}
+
+// title of the test, titles start with the padded line number to keep the order of the document
+func (c consoleCommand) title(width int) string {
+	return fmt.Sprintf("line %0*d: %s", width, c.line, c.command[0])
+}
+
+// consoleTests converts the commands to tests, assertions returns the assertions of the output of a command
+func consoleTests(commands []*consoleCommand, lineCount int, assertions func(output []string) map[string]interface{}) map[string]interface{} {
+	width := len(strconv.Itoa(lineCount))
+	converted := make(map[string]interface{})
+	for _, c := range commands {
+		test := map[string]interface{}{
+			"command":   strings.Join(c.command, "\n"),
+			"exit-code": c.exitCode,
+		}
+		if output := assertions(c.output); output != nil {
+			test["output"] = output
+		}
+		if c.skip {
+			test["skip"] = true
+		}
+		converted[c.title(width)] = test
+	}
+
+	return map[string]interface{}{"tests": converted}
+}
+
+// parseMarkdown extracts the commands of all console blocks as tests.
+// A command starts with "$ ", it is continued by lines starting with "> " and followed by its expected output.
+// The exit code can be set with a "[N]" line after the output, blocks with the info "skip" are skipped.
+func parseMarkdown(content []byte) map[string]interface{} {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	var tests []*consoleCommand
+	for i := 0; i < len(lines); i++ {
+		m := markdownFence.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+
+		indent, fence, info := len(m[1]), m[2], strings.Fields(m[3])
+		end := closingFence(lines, i+1, fence)
+		if len(info) > 0 && isMarkdownLanguage(info[0]) {
+			tests = append(tests, parseConsoleBlock(lines[i+1:end], i+2, indent, hasWord(info[1:], "skip"))...)
+		}
+		i = end
+	}
+
+	return consoleTests(tests, len(lines), func(output []string) map[string]interface{} {
+		if text := strings.Join(output, "\n"); strings.TrimSpace(text) != "" {
+			return map[string]interface{}{"exactly": text}
+		}
+		return nil
+	})
+}
+
+// parseConsoleBlock parses the lines of a console block, first is the line number of the first line
+func parseConsoleBlock(lines []string, first int, indent int, skip bool) []*consoleCommand {
+	var tests []*consoleCommand
+	var current *consoleCommand
+	for i, l := range lines {
+		l = trimIndent(l, indent)
+
+		switch {
+		case strings.HasPrefix(l, consolePrompt):
+			current = &consoleCommand{line: first + i, skip: skip}
+			current.command = []string{strings.TrimPrefix(l, consolePrompt)}
+			tests = append(tests, current)
+		case current == nil:
+			// text before the first command is not part of a test
+		case strings.HasPrefix(l, consoleContinue) && len(current.output) == 0:
+			current.command = append(current.command, strings.TrimPrefix(l, consoleContinue))
+		default:
+			current.output = append(current.output, l)
+		}
+	}
+
+	for _, t := range tests {
+		t.output = trimExitCode(t)
+	}
+	return tests
+}
+
+// trimExitCode removes the exit code marker after the output and sets the exit code of the test
+func trimExitCode(t *consoleCommand) []string {
+	output := t.output
+	for len(output) > 0 && strings.TrimSpace(output[len(output)-1]) == "" {
+		output = output[:len(output)-1]
+	}
+	if len(output) == 0 {
+		return output
+	}
+
+	if m := exitCodeMarker.FindStringSubmatch(strings.TrimSpace(output[len(output)-1])); m != nil {
+		t.exitCode, _ = strconv.Atoi(m[1])
+		output = output[:len(output)-1]
+	}
+	return output
+}
+
+// closingFence returns the index of the fence which closes the block, or the end of the document
+func closingFence(lines []string, from int, fence string) int {
+	for i := from; i < len(lines); i++ {
+		l := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(l, fence) && strings.Trim(l, fence[:1]) == "" {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// trimIndent removes the indentation of the fence from the line
+func trimIndent(line string, indent int) string {
+	for i := 0; i < indent && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+func isMarkdownLanguage(lang string) bool {
+	return hasWord(markdownLanguages, strings.ToLower(lang))
+}
+
+func hasWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const markdownDoc = "# Usage\n" +
+	"\n" +
+	"```console\n" +
+	"$ echo hello\n" +
+	"hello\n" +
+	"$ echo one &&\n" +
+	"> echo two\n" +
+	"one\n" +
+	"two\n" +
+	"\n" +
+	"$ exit 3\n" +
+	"[3]\n" +
+	"```\n" +
+	"\n" +
+	"```bash\n" +
+	"$ echo ignored\n" +
+	"```\n" +
+	"\n" +
+	" - list item\n" +
+	"\n" +
+	"   ~~~console skip\n" +
+	"   $ rm -rf build\n" +
+	"   ~~~\n"
+
+func Test_ParseMarkdown(t *testing.T) {
+	s := ParseMarkdown([]byte(markdownDoc), "README.md")
+	tests := sortedSuite(s).GetTests()
+
+	assert.Len(t, tests, 4)
+
+	assert.Equal(t, "line 04: echo hello", tests[0].Title)
+	assert.Equal(t, "echo hello", tests[0].Command.Cmd)
+	assert.Equal(t, "hello", tests[0].Expected.Output.Exactly)
+	assert.Equal(t, 0, tests[0].Expected.ExitCode)
+	assert.Equal(t, "README.md", tests[0].FileName)
+
+	assert.Equal(t, "line 06: echo one &&", tests[1].Title)
+	assert.Equal(t, "echo one &&\necho two", tests[1].Command.Cmd)
+	assert.Equal(t, "one\ntwo", tests[1].Expected.Output.Exactly)
+
+	assert.Equal(t, "line 11: exit 3", tests[2].Title)
+	assert.Equal(t, 3, tests[2].Expected.ExitCode)
+	assert.Equal(t, "", tests[2].Expected.Output.Exactly)
+
+	assert.Equal(t, "line 22: rm -rf build", tests[3].Title)
+	assert.True(t, tests[3].Skip)
+}
+
+func Test_ParseMarkdown_OrderOfTitles(t *testing.T) {
+	doc := "```console\n$ echo b\n```\n"
+	for i := 0; i < 10; i++ {
+		doc += "\n"
+	}
+	doc += "```console\n$ echo a\n```\n"
+
+	tests := sortedSuite(ParseMarkdown([]byte(doc), "")).GetTests()
+
+	assert.Equal(t, "line 02: echo b", tests[0].Title)
+	assert.Equal(t, "line 15: echo a", tests[1].Title)
+}
+
+func Test_ParseMarkdown_UnclosedBlock(t *testing.T) {
+	tests := ParseMarkdown([]byte("```console\n$ echo hello\nhello\n"), "").GetTests()
+
+	assert.Len(t, tests, 1)
+	assert.Equal(t, "hello", tests[0].Expected.Output.Exactly)
+}
+
+func Test_ParseMarkdown_WithoutConsoleBlocks(t *testing.T) {
+	assert.Empty(t, ParseMarkdown([]byte("# Title\n\n```yaml\ntests: {}\n```\n"), "").GetTests())
+}
@@ -0,0 +1,212 @@
+package suite
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+)
+
+// schema is a JSON Schema object
+type schema map[string]interface{}
+
+// suiteKeys are the keys of the root of a suite
+var suiteKeys = []string{"config", "include", "nodes", "tags", "templates", "tests"}
+
+// fieldSchemas are the schemas of the fields which are parsed from raw values, see YAMLSuiteConf.UnmarshalYAML
+var fieldSchemas = map[string]schema{
+	"extends":   stringOrList(),
+	"validate":  stringOrList(),
+	"exit-code": ref("exit-code"),
+	"stdout":    ref("expected-out"),
+	"stderr":    ref("expected-out"),
+	"output":    ref("expected-out"),
+	// run-if and skip-if
+	"os":      stringOrList(),
+	"arch":    stringOrList(),
+	"binary":  stringOrList(),
+	"env":     stringOrList(),
+	"command": stringOrList(),
+}
+
+// assertionSchemas are the schemas of the values of the built-in assertions, custom assertions accept any value
+var assertionSchemas = map[string]schema{
+	"exactly":           text(),
+	"contains":          oneOrList(schema{"anyOf": []interface{}{text(), typeSchema(reflect.TypeOf(matcher.ContainsCount{}))}}),
+	"line-count":        {"type": "integer"},
+	"lines":             {"type": "object", "propertyNames": schema{"pattern": `^[0-9]+$`}, "additionalProperties": text()},
+	"not-contains":      stringOrList(),
+	"json":              {"type": "object", "additionalProperties": text()},
+	"xml":               {"type": "object", "additionalProperties": text()},
+	"file":              text(),
+	"table":             typeSchema(reflect.TypeOf(matcher.TableExpectation{})),
+	"byte-size":         {"type": "integer", "minimum": 0},
+	"sha256":            {"type": "string", "pattern": `^\s*[0-9a-fA-F]{64}\s*$`},
+	"md5":               {"type": "string", "pattern": `^\s*[0-9a-fA-F]{32}\s*$`},
+	"binary-file":       text(),
+	"contains-in-order": {"type": "array", "items": text()},
+	matcher.AnyOfKey:    {"type": "array", "items": ref("assertions")},
+	matcher.AllOfKey:    {"type": "array", "items": ref("assertions")},
+	matcher.NotKey:      ref("assertions"),
+	"transcript":        stringOrList(),
+}
+
+// Schema returns the JSON Schema of the yaml suites.
+// It is generated from YAMLSuiteConf, YAMLTest, YAMLNodeConf and the registered assertions,
+// editors can use it to complete and validate suites.
+func Schema() ([]byte, error) {
+	return schemaOf(matcher.Assertions())
+}
+
+// schemaOf returns the JSON Schema of the yaml suites which accept the given assertions
+func schemaOf(assertions []matcher.Assertion) ([]byte, error) {
+	properties := schema{}
+	for _, key := range suiteKeys {
+		switch key {
+		case "tests", "templates":
+			properties[key] = schema{"type": []string{"object", "null"}, "additionalProperties": ref("test")}
+		case "config":
+			properties[key] = ref("config")
+		case "nodes":
+			properties[key] = schema{"type": []string{"object", "null"}, "additionalProperties": ref("node")}
+		case "include":
+			properties[key] = stringOrList()
+		case "tags":
+			properties[key] = typeSchema(reflect.TypeOf([]string{}))
+		default:
+			panic(fmt.Sprintf("Key %s has no schema", key))
+		}
+	}
+
+	test := typeSchema(reflect.TypeOf(YAMLTest{}))
+	test["type"] = []string{"object", "null"}
+
+	node := typeSchema(reflect.TypeOf(YAMLNodeConf{}))
+	node["properties"].(schema)["type"] = schema{"type": "string", "enum": nodeTypes}
+
+	s := schema{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "commander test suite",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+		"definitions": schema{
+			"test":         test,
+			"config":       typeSchema(reflect.TypeOf(YAMLTestConfigConf{})),
+			"node":         node,
+			"exit-code":    exitCodeSchema(),
+			"expected-out": schema{"anyOf": []interface{}{schema{"type": []string{"string", "number", "boolean", "null"}}, ref("assertions")}},
+			"assertions":   assertionsSchema(assertions),
+		},
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// typeSchema returns the schema of the yaml fields of the type, fields without a static type are looked up in fieldSchemas
+func typeSchema(t reflect.Type) schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return text()
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int:
+		return schema{"type": "integer"}
+	case reflect.Slice:
+		return schema{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return schema{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := schema{}
+		for _, key := range yamlKeys(t) {
+			f := yamlField(t, key)
+			if f.Type == reflect.TypeOf(YAMLTestConfigConf{}) {
+				properties[key] = ref("config")
+				continue
+			}
+			if f.Type.Kind() != reflect.Interface {
+				properties[key] = typeSchema(f.Type)
+				continue
+			}
+
+			s, ok := fieldSchemas[key]
+			if !ok {
+				panic(fmt.Sprintf("Field %s of %s has no schema", key, t))
+			}
+			properties[key] = s
+		}
+		return schema{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	panic(fmt.Sprintf("Type %s has no schema", t))
+}
+
+// assertionsSchema returns the schema of the map of assertions of stdout, stderr and output
+func assertionsSchema(assertions []matcher.Assertion) schema {
+	var keys []string
+	properties := schema{
+		"normalize": schema{"type": "array", "items": schema{"anyOf": []interface{}{
+			schema{"type": "string", "enum": []string{runtime.StripANSI, runtime.CollapseWhitespace, runtime.Lowercase, runtime.ReplaceTmpDir, runtime.ReplaceHome}},
+			schema{
+				"type":                 "object",
+				"properties":           schema{"regex": text(), "replace": text()},
+				"required":             []string{"regex"},
+				"additionalProperties": false,
+			},
+		}}},
+		"message": text(),
+	}
+
+	for _, a := range assertions {
+		keys = append(keys, a.Key)
+		if s, ok := assertionSchemas[a.Key]; ok {
+			properties[a.Key] = s
+			continue
+		}
+		properties[a.Key] = schema{}
+	}
+
+	properties["messages"] = schema{
+		"type":                 "object",
+		"propertyNames":        schema{"enum": keys},
+		"additionalProperties": text(),
+	}
+
+	return schema{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+func exitCodeSchema() schema {
+	code := schema{"type": "integer"}
+	codeOrRange := schema{"anyOf": []interface{}{code, schema{"type": "string", "pattern": `^[0-9]+-[0-9]+$`}}}
+
+	return schema{"anyOf": []interface{}{
+		code,
+		schema{"type": "string", "pattern": `^(any|[0-9]+-[0-9]+)$`},
+		schema{"type": "array", "items": codeOrRange, "minItems": 1},
+		schema{
+			"type":                 "object",
+			"properties":           schema{"not": schema{"anyOf": []interface{}{code, schema{"type": "array", "items": code, "minItems": 1}}}},
+			"additionalProperties": false,
+		},
+	}}
+}
+
+func ref(definition string) schema {
+	return schema{"$ref": "#/definitions/" + definition}
+}
+
+func stringOrList() schema {
+	return oneOrList(text())
+}
+
+func oneOrList(s schema) schema {
+	return schema{"anyOf": []interface{}{s, schema{"type": "array", "items": s}}}
+}
+
+// text is the schema of texts, other scalars are converted to texts by the parser
+func text() schema {
+	return schema{"type": []string{"string", "number", "boolean"}}
+}
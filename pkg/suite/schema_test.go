@@ -0,0 +1,100 @@
+package suite
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+)
+
+// schemaFile is the published schema, it is updated with: go run ./cmd/commander schema > docs/commander.schema.json
+const schemaFile = "../../docs/commander.schema.json"
+
+// builtInAssertions are registered before the tests are executed, tests may register custom assertions
+var builtInAssertions = matcher.Assertions()
+
+func Test_Schema_IsPublished(t *testing.T) {
+	s, err := schemaOf(builtInAssertions)
+	assert.Nil(t, err)
+
+	published, err := os.ReadFile(schemaFile)
+	assert.Nil(t, err)
+	assert.Equal(t, string(published), string(s)+"\n", "%s is outdated", schemaFile)
+}
+
+// Test_Schema_KeysAreParsed checks that all keys of the schema are accepted by the parser
+func Test_Schema_KeysAreParsed(t *testing.T) {
+	s := decodeSchema(t)
+	definitions := s["definitions"].(map[string]interface{})
+
+	for _, key := range propertyNames(s) {
+		assert.NotPanics(t, func() { ParseYAML([]byte(key+":\n"), "") }, key)
+	}
+	for _, key := range propertyNames(definitions["test"]) {
+		assert.NotPanics(t, func() { ParseYAML([]byte("tests:\n  echo:\n    "+key+":\n"), "") }, key)
+	}
+	for _, key := range propertyNames(definitions["config"]) {
+		assert.NotPanics(t, func() { ParseYAML([]byte("config:\n  "+key+":\n"), "") }, key)
+	}
+	for _, key := range propertyNames(definitions["node"]) {
+		assert.NotPanics(t, func() { ParseYAML([]byte("nodes:\n  node:\n    "+key+":\n"), "") }, key)
+	}
+
+	assert.Panics(t, func() { ParseYAML([]byte("unknown:\n"), "") })
+	assert.Panics(t, func() { ParseYAML([]byte("tests:\n  echo:\n    unknown:\n"), "") })
+}
+
+func Test_Schema_ContainsAllAssertions(t *testing.T) {
+	keys := []string{"message", "messages", "normalize"}
+	for _, a := range builtInAssertions {
+		keys = append(keys, a.Key)
+		assert.Contains(t, assertionSchemas, a.Key, "assertion %s has no schema", a.Key)
+	}
+	sort.Strings(keys)
+
+	assertions := decodeSchema(t)["definitions"].(map[string]interface{})["assertions"]
+	assert.Equal(t, keys, propertyNames(assertions))
+	assert.Len(t, assertionSchemas, len(builtInAssertions))
+}
+
+func Test_Schema_ContainsRegisteredAssertions(t *testing.T) {
+	t.Cleanup(matcher.RegisterAssertion(matcher.Assertion{Key: "schema-test-custom", Parse: matcher.ParseString, Matcher: matcher.TextMatcher{}}))
+
+	content, err := Schema()
+	assert.Nil(t, err)
+
+	var s map[string]interface{}
+	assert.Nil(t, json.Unmarshal(content, &s))
+	assert.Contains(t, propertyNames(s["definitions"].(map[string]interface{})["assertions"]), "schema-test-custom")
+}
+
+func Test_TypeSchema_FieldWithoutSchema(t *testing.T) {
+	type test struct {
+		Unknown interface{} `yaml:"unknown"`
+	}
+
+	assert.PanicsWithValue(t, "Field unknown of suite.test has no schema", func() { typeSchema(reflect.TypeOf(test{})) })
+}
+
+func decodeSchema(t *testing.T) map[string]interface{} {
+	content, err := schemaOf(builtInAssertions)
+	assert.Nil(t, err)
+
+	var s map[string]interface{}
+	assert.Nil(t, json.Unmarshal(content, &s))
+	return s
+}
+
+func propertyNames(s interface{}) []string {
+	var keys []string
+	for k := range s.(map[string]interface{})["properties"].(map[string]interface{}) {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
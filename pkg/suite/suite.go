@@ -21,8 +21,21 @@ type Suite struct {
 // overwriteConfigContent is an optional slice which overwrites the default configurations
 // fileName is the file that is under test
 func NewSuite(suiteContent, overwriteConfigContent []byte, fileName string) Suite {
+	return NewSuiteFromFile("", suiteContent, overwriteConfigContent, fileName)
+}
+
+// NewSuiteFromFile creates a suite like NewSuite from the suite file at path,
+// included suites are resolved relative to the directory of the file.
+// The format of the suite is detected by the extension of the path, see FormatFromPath.
+func NewSuiteFromFile(path string, suiteContent, overwriteConfigContent []byte, fileName string) Suite {
+	return NewSuiteFromFileWithFormat(path, FormatFromPath(path), suiteContent, overwriteConfigContent, fileName)
+}
+
+// NewSuiteFromFileWithFormat creates a suite like NewSuiteFromFile from a suite in the given format,
+// overwriteConfigContent is always yaml
+func NewSuiteFromFileWithFormat(path string, format Format, suiteContent, overwriteConfigContent []byte, fileName string) Suite {
 	overwriteConfig := ParseYAML(overwriteConfigContent, "default config")
-	s := ParseYAML(suiteContent, fileName)
+	s := parseSuite(suiteContent, format, path, fileName)
 
 	s.mergeConfigs(overwriteConfig.Config, overwriteConfig.Nodes)
 
@@ -116,6 +129,10 @@ func (s Suite) mergeConfigs(config runtime.GlobalTestConfig, nodes []runtime.Nod
 		s.Config.Interval = config.Interval
 	}
 
+	if s.Config.MaxOutput == "" {
+		s.Config.MaxOutput = config.MaxOutput
+	}
+
 	if !s.Config.InheritEnv {
 		s.Config.InheritEnv = config.InheritEnv
 	}
@@ -153,6 +170,10 @@ func (s Suite) mergeTestConfigs() {
 			s.TestCases[i].Command.Interval = s.Config.Interval
 		}
 
+		if s.TestCases[i].Command.MaxOutput == "" {
+			s.TestCases[i].Command.MaxOutput = s.Config.MaxOutput
+		}
+
 		if !s.TestCases[i].Command.InheritEnv {
 			s.TestCases[i].Command.InheritEnv = s.Config.InheritEnv
 		}
@@ -0,0 +1,137 @@
+package suite
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// addTemplates adds the raw templates of the suite, they take precedence over templates of included suites
+func (y *YAMLSuiteConf) addTemplates(templates map[interface{}]interface{}) {
+	if y.templates == nil {
+		y.templates = make(map[string]map[interface{}]interface{})
+	}
+
+	for k, v := range templates {
+		name := fmt.Sprint(k)
+		switch t := v.(type) {
+		case map[interface{}]interface{}:
+			y.templates[name] = t
+		case nil:
+			y.templates[name] = map[interface{}]interface{}{}
+		default:
+			panic(fmt.Sprintf("Failed to parse template '%s', expected a map, got %v", name, v))
+		}
+	}
+}
+
+// validateTemplates checks that all templates can be resolved and are valid tests
+func (y *YAMLSuiteConf) validateTemplates() {
+	var names []string
+	for name := range y.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		decodeTest(fmt.Sprintf("template '%s'", name), y.resolveTemplate(name, nil))
+	}
+}
+
+// extendTest merges the templates which are extended by the raw test, the values of the test take precedence
+func (y *YAMLSuiteConf) extendTest(title string, test map[interface{}]interface{}) YAMLTest {
+	base := map[interface{}]interface{}{}
+	for _, name := range toTemplateNames(test["extends"]) {
+		if _, ok := y.templates[name]; !ok {
+			panic(fmt.Sprintf("Test '%s' extends template '%s' which does not exist", title, name))
+		}
+		base = deepMerge(base, y.resolveTemplate(name, nil))
+	}
+
+	return decodeTest(fmt.Sprintf("test '%s'", title), deepMerge(base, withoutExtends(test)))
+}
+
+// resolveTemplate merges the template with the templates it extends, chain holds the templates which are resolved
+func (y *YAMLSuiteConf) resolveTemplate(name string, chain []string) map[interface{}]interface{} {
+	for _, c := range chain {
+		if c == name {
+			panic(fmt.Sprintf("Template cycle detected: %s -> %s", strings.Join(chain, " -> "), name))
+		}
+	}
+	chain = append(chain, name)
+
+	template := y.templates[name]
+	base := map[interface{}]interface{}{}
+	for _, parent := range toTemplateNames(template["extends"]) {
+		if _, ok := y.templates[parent]; !ok {
+			panic(fmt.Sprintf("Template '%s' extends template '%s' which does not exist", name, parent))
+		}
+		base = deepMerge(base, y.resolveTemplate(parent, chain))
+	}
+
+	return deepMerge(base, withoutExtends(template))
+}
+
+// deepMerge merges override into base, nested maps are merged and all other values of override replace the values of base
+func deepMerge(base, override map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := make(map[interface{}]interface{})
+	for k, v := range base {
+		merged[k] = v
+	}
+
+	for k, v := range override {
+		baseMap, baseOk := merged[k].(map[interface{}]interface{})
+		overrideMap, overrideOk := v.(map[interface{}]interface{})
+		if baseOk && overrideOk {
+			merged[k] = deepMerge(baseMap, overrideMap)
+			continue
+		}
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// decodeTest decodes the merged raw test
+func decodeTest(name string, raw map[interface{}]interface{}) YAMLTest {
+	content, err := yaml.Marshal(raw)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %s", name, err))
+	}
+
+	test := YAMLTest{}
+	if err := yaml.UnmarshalStrict(content, &test); err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %s", name, err))
+	}
+	return test
+}
+
+func withoutExtends(raw map[interface{}]interface{}) map[interface{}]interface{} {
+	m := make(map[interface{}]interface{})
+	for k, v := range raw {
+		if k != "extends" {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// toTemplateNames converts the extends value to a list of template names
+func toTemplateNames(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var names []string
+		for _, n := range v {
+			names = append(names, fmt.Sprint(n))
+		}
+		return names
+	default:
+		panic(fmt.Sprintf("Failed to parse extends, expected a template name or a list of names, got %v", value))
+	}
+}
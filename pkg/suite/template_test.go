@@ -0,0 +1,160 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Extends_DeepMergesTemplate(t *testing.T) {
+	yaml := []byte(`
+templates:
+  cli:
+    config:
+      env:
+        LANG: C
+        MODE: template
+      timeout: 5s
+    stderr:
+      exactly: ""
+      line-count: 0
+    exit-code: 0
+
+tests:
+  echo hello:
+    extends: cli
+    config:
+      env:
+        MODE: test
+    stdout: hello
+
+  echo bye:
+    extends: cli
+    exit-code: 1
+    stderr:
+      line-count: 2
+`)
+	s := ParseYAML(yaml, "")
+
+	hello, _ := s.GetTestByTitle("echo hello")
+	assert.Equal(t, "echo hello", hello.Command.Cmd)
+	assert.Equal(t, map[string]string{"LANG": "C", "MODE": "test"}, hello.Command.Env)
+	assert.Equal(t, "5s", hello.Command.Timeout)
+	assert.Equal(t, []string{"hello"}, hello.Expected.Stdout.Contains)
+	assert.Equal(t, 0, hello.Expected.ExitCode)
+
+	bye, _ := s.GetTestByTitle("echo bye")
+	assert.Equal(t, 1, bye.Expected.ExitCode)
+	assert.Equal(t, 2, bye.Expected.Stderr.LineCount)
+	assert.Equal(t, map[string]string{"LANG": "C", "MODE": "template"}, bye.Command.Env)
+}
+
+func Test_Extends_TemplatesCanExtendTemplates(t *testing.T) {
+	yaml := []byte(`
+templates:
+  base:
+    config:
+      timeout: 5s
+      retries: 2
+  slow:
+    extends: base
+    config:
+      timeout: 1m
+  quiet:
+    stderr:
+      line-count: 0
+
+tests:
+  sleep 1:
+    extends: [slow, quiet]
+`)
+	test, _ := ParseYAML(yaml, "").GetTestByTitle("sleep 1")
+
+	assert.Equal(t, "1m", test.Command.Timeout)
+	assert.Equal(t, 2, test.Command.Retries)
+	assert.Equal(t, 0, test.Expected.Stderr.LineCount)
+}
+
+func Test_Extends_UnknownTemplate(t *testing.T) {
+	yaml := []byte(`
+tests:
+  echo hello:
+    extends: missing
+`)
+	assert.PanicsWithValue(t, "Test 'echo hello' extends template 'missing' which does not exist", func() {
+		ParseYAML(yaml, "")
+	})
+}
+
+func Test_Extends_TemplateCycle(t *testing.T) {
+	yaml := []byte(`
+templates:
+  a:
+    extends: b
+  b:
+    extends: a
+tests:
+  echo hello:
+    exit-code: 0
+`)
+	assert.PanicsWithValue(t, "Template cycle detected: a -> b -> a", func() {
+		ParseYAML(yaml, "")
+	})
+}
+
+func Test_Extends_InvalidTemplate(t *testing.T) {
+	yaml := []byte(`
+templates:
+  cli:
+    stdot: hello
+tests:
+  echo hello:
+    exit-code: 0
+`)
+	assert.Panics(t, func() { ParseYAML(yaml, "") })
+}
+
+func Test_Extends_TemplateFromInclude(t *testing.T) {
+	dir := writeSuites(t, map[string]string{
+		"main.yaml": `
+include: templates.yaml
+templates:
+  local:
+    extends: shared
+    stderr:
+      line-count: 0
+tests:
+  echo hello:
+    extends: local
+`,
+		"templates.yaml": `
+templates:
+  shared:
+    config:
+      timeout: 5s
+`,
+	})
+
+	test, err := loadTestSuite(t, dir, "main.yaml").GetTestByTitle("echo hello")
+	assert.Nil(t, err)
+	assert.Equal(t, "5s", test.Command.Timeout)
+	assert.Equal(t, 0, test.Expected.Stderr.LineCount)
+}
+
+func Test_deepMerge(t *testing.T) {
+	base := map[interface{}]interface{}{
+		"a": map[interface{}]interface{}{"x": 1, "y": 2},
+		"b": []interface{}{1, 2},
+		"c": "base",
+	}
+	override := map[interface{}]interface{}{
+		"a": map[interface{}]interface{}{"y": 3},
+		"b": []interface{}{3},
+	}
+
+	assert.Equal(t, map[interface{}]interface{}{
+		"a": map[interface{}]interface{}{"x": 1, "y": 3},
+		"b": []interface{}{3},
+		"c": "base",
+	}, deepMerge(base, override))
+}
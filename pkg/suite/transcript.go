@@ -0,0 +1,109 @@
+package suite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/commander-cli/commander/v2/pkg/matcher"
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+)
+
+// indentation of commands and output in transcripts, all other lines are comments
+const transcriptIndent = "  "
+
+// ParseTranscript parses the Suite from a cram style transcript
+func ParseTranscript(content []byte, fileName string) Suite {
+	return Parse(content, FormatTranscript, fileName)
+}
+
+// parseTranscript converts the commands of a transcript to tests, the raw output is matched exactly by the transcript assertion.
+// Commands start with "  $ ", are continued by lines starting with "  > " and are followed by the expected output
+// which is indented by two spaces. The exit code can be set with a "  [N]" line after the output.
+func parseTranscript(content []byte) map[string]interface{} {
+	lines := transcriptLines(content)
+	converted := consoleTests(parseTranscriptCommands(lines), len(lines), func(output []string) map[string]interface{} {
+		return map[string]interface{}{"transcript": append([]string{}, output...)}
+	})
+
+	// leading whitespace and empty lines are part of the expected output
+	for _, test := range converted["tests"].(map[string]interface{}) {
+		test.(map[string]interface{})["raw-output"] = true
+	}
+	return converted
+}
+
+func parseTranscriptCommands(lines []string) []*consoleCommand {
+	var commands []*consoleCommand
+	var current *consoleCommand
+	for i, l := range lines {
+		if !strings.HasPrefix(l, transcriptIndent) {
+			// comments end the output of a command
+			current = nil
+			continue
+		}
+		l = strings.TrimPrefix(l, transcriptIndent)
+
+		switch {
+		case strings.HasPrefix(l, consolePrompt):
+			current = &consoleCommand{line: i + 1, outputStart: i + 1, outputEnd: i + 1}
+			current.command = []string{strings.TrimPrefix(l, consolePrompt)}
+			commands = append(commands, current)
+		case current == nil:
+			// indented text without a command is a comment
+		case strings.HasPrefix(l, consoleContinue) && len(current.output) == 0:
+			current.command = append(current.command, strings.TrimPrefix(l, consoleContinue))
+			current.outputStart, current.outputEnd = i+1, i+1
+		default:
+			current.output = append(current.output, l)
+			current.outputEnd = i + 1
+		}
+	}
+
+	for _, c := range commands {
+		c.output = trimExitCode(c)
+	}
+	return commands
+}
+
+// UpdateTranscript replaces the expected output and exit codes of the transcript with the results of its tests.
+// Annotated lines which match the new output are kept, tests which were skipped or could not be executed are not changed.
+func UpdateTranscript(content []byte, results []runtime.TestResult) []byte {
+	lines := transcriptLines(content)
+	commands := parseTranscriptCommands(lines)
+	width := len(fmt.Sprint(len(lines)))
+
+	byTitle := make(map[string]runtime.TestResult)
+	for _, r := range results {
+		if _, ok := byTitle[r.TestCase.Title]; !ok && !r.Skipped && r.TestCase.Result.Error == nil {
+			byTitle[r.TestCase.Title] = r
+		}
+	}
+
+	// commands are replaced from the end to keep the indexes of the previous commands
+	for i := len(commands) - 1; i >= 0; i-- {
+		c := commands[i]
+		r, ok := byTitle[c.title(width)]
+		if !ok {
+			continue
+		}
+
+		var updated []string
+		for j, l := range matcher.TranscriptLines(r.TestCase.Result.Output) {
+			if j < len(c.output) && matcher.MatchTranscriptLine(c.output[j], l) {
+				l = c.output[j]
+			}
+			updated = append(updated, transcriptIndent+l)
+		}
+		if code := r.TestCase.Result.ExitCode; code != 0 {
+			updated = append(updated, fmt.Sprintf("%s[%d]", transcriptIndent, code))
+		}
+
+		lines = append(lines[:c.outputStart], append(updated, lines[c.outputEnd:]...)...)
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func transcriptLines(content []byte) []string {
+	return strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+}
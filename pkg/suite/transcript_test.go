@@ -0,0 +1,101 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/commander-cli/commander/v2/pkg/runtime"
+)
+
+const transcript = `Comments are not indented.
+
+  $ echo hello
+  hello
+  $ printf 'a\n\nb\n'
+  a
+  
+  b
+
+Exit codes follow the output:
+
+  $ echo failed &&
+  > exit 2
+  failed
+  [2]
+  $ date +%Y
+  \d{4} (re)
+`
+
+func Test_ParseTranscript(t *testing.T) {
+	tests := sortedSuite(ParseTranscript([]byte(transcript), "cli.t")).GetTests()
+
+	assert.Len(t, tests, 4)
+
+	assert.Equal(t, "line 03: echo hello", tests[0].Title)
+	assert.Equal(t, []string{"hello"}, tests[0].Expected.Output.Transcript)
+	assert.Equal(t, "cli.t", tests[0].FileName)
+
+	assert.Equal(t, "line 05: printf 'a\\n\\nb\\n'", tests[1].Title)
+	assert.Equal(t, []string{"a", "", "b"}, tests[1].Expected.Output.Transcript)
+
+	assert.Equal(t, "line 12: echo failed &&", tests[2].Title)
+	assert.Equal(t, "echo failed &&\nexit 2", tests[2].Command.Cmd)
+	assert.Equal(t, []string{"failed"}, tests[2].Expected.Output.Transcript)
+	assert.Equal(t, 2, tests[2].Expected.ExitCode)
+
+	assert.Equal(t, []string{`\d{4} (re)`}, tests[3].Expected.Output.Transcript)
+
+	for _, test := range tests {
+		assert.True(t, test.Command.RawOutput)
+	}
+}
+
+func Test_ParseTranscript_ExpectsEmptyOutput(t *testing.T) {
+	tests := ParseTranscript([]byte("  $ true\n"), "").GetTests()
+
+	assert.Equal(t, []string{}, tests[0].Expected.Output.Transcript)
+}
+
+func Test_UpdateTranscript(t *testing.T) {
+	content := []byte(`Test:
+
+  $ echo hello
+  wrong
+  $ ls missing
+  [1]
+  $ date +%Y
+  \d{4} (re)
+  old
+  $ echo skipped
+  skipped
+`)
+	result := func(title string, output string, exitCode int) runtime.TestResult {
+		return runtime.TestResult{TestCase: runtime.TestCase{
+			Title:  title,
+			Result: runtime.CommandResult{Output: output, ExitCode: exitCode},
+		}}
+	}
+
+	results := []runtime.TestResult{
+		result("line 03: echo hello", "  hello\n\nworld\n", 0),
+		result("line 05: ls missing", "ls: missing: No such file", 2),
+		result("line 07: date +%Y", "2026", 0),
+		{TestCase: runtime.TestCase{Title: "line 10: echo skipped"}, Skipped: true},
+	}
+
+	assert.Equal(t, `Test:
+
+  $ echo hello
+    hello
+  
+  world
+  $ ls missing
+  ls: missing: No such file
+  [2]
+  $ date +%Y
+  \d{4} (re)
+  $ echo skipped
+  skipped
+`, string(UpdateTranscript(content, results)))
+}
@@ -3,10 +3,11 @@ package suite
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
+	"github.com/commander-cli/commander/v2/pkg/matcher"
 	"github.com/commander-cli/commander/v2/pkg/runtime"
 )
 
@@ -15,6 +16,12 @@ type YAMLSuiteConf struct {
 	Tests  map[string]YAMLTest     `yaml:"tests"`
 	Config YAMLTestConfigConf      `yaml:"config,omitempty"`
 	Nodes  map[string]YAMLNodeConf `yaml:"nodes,omitempty"`
+	// Include holds paths or globs of suites whose tests, nodes and config are merged, see includeLoader
+	Include []string `yaml:"include,omitempty"`
+	// Tags are added to all tests of the suite
+	Tags []string `yaml:"tags,omitempty"`
+	// templates holds the raw templates which can be extended by tests, including the templates of included suites
+	templates map[string]map[interface{}]interface{}
 }
 
 // YAMLTestConfigConf is a struct to represent the test config
@@ -26,6 +33,7 @@ type YAMLTestConfigConf struct {
 	Retries    int               `yaml:"retries,omitempty"`
 	Interval   string            `yaml:"interval,omitempty"`
 	Nodes      []string          `yaml:"nodes,omitempty"`
+	MaxOutput  string            `yaml:"max-output,omitempty"`
 }
 
 type YAMLNodeConf struct {
@@ -42,23 +50,41 @@ type YAMLNodeConf struct {
 
 // YAMLTest represents a test in the yaml test suite
 type YAMLTest struct {
-	Title    string             `yaml:"-"`
-	Command  string             `yaml:"command,omitempty"`
-	ExitCode int                `yaml:"exit-code"`
-	Stdout   interface{}        `yaml:"stdout,omitempty"`
-	Stderr   interface{}        `yaml:"stderr,omitempty"`
-	Config   YAMLTestConfigConf `yaml:"config,omitempty"`
-	Skip     bool               `yaml:"skip,omitempty"`
+	Title       string             `yaml:"-"`
+	Description string             `yaml:"description,omitempty"`
+	Extends     interface{}        `yaml:"extends,omitempty"`
+	Command     string             `yaml:"command,omitempty"`
+	ExitCode    interface{}        `yaml:"exit-code"`
+	Stdout      interface{}        `yaml:"stdout,omitempty"`
+	Stderr      interface{}        `yaml:"stderr,omitempty"`
+	Output      interface{}        `yaml:"output,omitempty"`
+	Config      YAMLTestConfigConf `yaml:"config,omitempty"`
+	Skip        bool               `yaml:"skip,omitempty"`
+	Validate    interface{}        `yaml:"validate,omitempty"`
+	RawOutput   bool               `yaml:"raw-output,omitempty"`
+	Tags        []string           `yaml:"tags,omitempty"`
+	RunIf       *YAMLCondition     `yaml:"run-if,omitempty"`
+	SkipIf      *YAMLCondition     `yaml:"skip-if,omitempty"`
+}
+
+// YAMLCondition represents the run-if and skip-if properties of a test, all values are a string or a list
+type YAMLCondition struct {
+	OS      interface{} `yaml:"os,omitempty"`
+	Arch    interface{} `yaml:"arch,omitempty"`
+	Binary  interface{} `yaml:"binary,omitempty"`
+	Env     interface{} `yaml:"env,omitempty"`
+	Command interface{} `yaml:"command,omitempty"`
 }
 
 // ParseYAML parses the Suite from a yaml byte slice
 func ParseYAML(content []byte, fileName string) Suite {
-	yamlConfig := YAMLSuiteConf{}
+	return parseSuite(content, FormatYAML, "", fileName)
+}
 
-	err := yaml.UnmarshalStrict(content, &yamlConfig)
-	if err != nil {
-		panic(err.Error())
-	}
+// parseSuite parses the suite from the file at path, includes are resolved relative to its directory.
+// If path is empty includes are resolved relative to the current working directory.
+func parseSuite(content []byte, format Format, path string, fileName string) Suite {
+	yamlConfig := loadSuite(content, format, path, fileName)
 
 	tests := convertYAMLSuiteConfToTestCases(yamlConfig, fileName)
 
@@ -72,6 +98,7 @@ func ParseYAML(content []byte, fileName string) Suite {
 			Retries:    yamlConfig.Config.Retries,
 			Interval:   yamlConfig.Config.Interval,
 			Nodes:      yamlConfig.Config.Nodes,
+			MaxOutput:  yamlConfig.Config.MaxOutput,
 		},
 		Nodes: convertNodes(yamlConfig.Nodes),
 	}
@@ -102,8 +129,10 @@ func convertNodes(nodeConfs map[string]YAMLNodeConf) []runtime.Node {
 func convertYAMLSuiteConfToTestCases(conf YAMLSuiteConf, fileName string) []runtime.TestCase {
 	var tests []runtime.TestCase
 	for _, t := range conf.Tests {
+		exitCode, exitCodes := toExitCodeExpectation(t.ExitCode)
 		tests = append(tests, runtime.TestCase{
-			Title: t.Title,
+			Title:       t.Title,
+			Description: t.Description,
 			Command: runtime.CommandUnderTest{
 				Cmd:        t.Command,
 				InheritEnv: t.Config.InheritEnv,
@@ -112,32 +141,234 @@ func convertYAMLSuiteConfToTestCases(conf YAMLSuiteConf, fileName string) []runt
 				Timeout:    t.Config.Timeout,
 				Retries:    t.Config.Retries,
 				Interval:   t.Config.Interval,
+				MaxOutput:  t.Config.MaxOutput,
+				// raw-output is also enabled by binary assertions, see UnmarshalYAML
+				RawOutput: t.Stdout.(runtime.ExpectedOut).RawOutput,
 			},
 			Expected: runtime.Expected{
-				ExitCode: t.ExitCode,
-				Stdout:   t.Stdout.(runtime.ExpectedOut),
-				Stderr:   t.Stderr.(runtime.ExpectedOut),
+				ExitCode:   exitCode,
+				ExitCodes:  exitCodes,
+				Stdout:     t.Stdout.(runtime.ExpectedOut),
+				Stderr:     t.Stderr.(runtime.ExpectedOut),
+				Output:     t.Output.(runtime.ExpectedOut),
+				Validators: toValidators(t.Validate),
 			},
 			Nodes:    t.Config.Nodes,
 			FileName: fileName,
 			Skip:     t.Skip,
+			Tags:     mergeTags(conf.Tags, t.Tags),
+			RunIf:    toCondition(t.RunIf, "run-if"),
+			SkipIf:   toCondition(t.SkipIf, "skip-if"),
 		})
 	}
 
 	return tests
 }
 
+// mergeTags returns the tags of the suite followed by the tags of the test, duplicates are removed
+func mergeTags(suite []string, test []string) []string {
+	var tags []string
+	for _, t := range append(append([]string{}, suite...), test...) {
+		if indexOf(tags, t) < 0 {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// Convert the exit-code property. A single int is returned as the exact exit code,
+// lists, ranges like "1-125", "any" and maps with a "not" key are converted to an ExitCodeExpectation
+func toExitCodeExpectation(value interface{}) (int, *matcher.ExitCodeExpectation) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case string:
+		if v == "any" {
+			return 0, &matcher.ExitCodeExpectation{Any: true}
+		}
+		exp := &matcher.ExitCodeExpectation{}
+		addExitCode(exp, v)
+		return 0, exp
+	case []interface{}:
+		// an empty list would accept every exit code, use any instead
+		if len(v) == 0 {
+			panic("Invalid exit-code [], expected at least one exit code or range, use any to accept all exit codes")
+		}
+		exp := &matcher.ExitCodeExpectation{}
+		for _, c := range v {
+			addExitCode(exp, c)
+		}
+		return 0, exp
+	case map[interface{}]interface{}:
+		exp := &matcher.ExitCodeExpectation{}
+		for k, n := range v {
+			if k != "not" {
+				panic(fmt.Sprintf("Key %s is not allowed in exit-code.", k))
+			}
+
+			switch n := n.(type) {
+			case int:
+				exp.Not = append(exp.Not, n)
+			case []interface{}:
+				if len(n) == 0 {
+					panic("Invalid exit-code [] given in not, expected at least one exit code")
+				}
+				for _, c := range n {
+					code, ok := c.(int)
+					if !ok {
+						panic(fmt.Sprintf("Invalid exit-code %v given in not", c))
+					}
+					exp.Not = append(exp.Not, code)
+				}
+			default:
+				panic(fmt.Sprintf("Invalid exit-code %v given in not", n))
+			}
+		}
+		return 0, exp
+	default:
+		panic(fmt.Sprintf("Invalid exit-code %v", value))
+	}
+}
+
+// exitCodeRangeRegex matches exit code ranges like 1-125
+var exitCodeRangeRegex = regexp.MustCompile(`^(\d+)-(\d+)$`)
+
+// Add a single exit code or a range like "1-125" to the expectation
+func addExitCode(exp *matcher.ExitCodeExpectation, value interface{}) {
+	if code, ok := value.(int); ok {
+		exp.Codes = append(exp.Codes, code)
+		return
+	}
+
+	if s, ok := value.(string); ok {
+		if m := exitCodeRangeRegex.FindStringSubmatch(s); m != nil {
+			r := matcher.ExitCodeRange{}
+			r.Min, _ = strconv.Atoi(m[1])
+			r.Max, _ = strconv.Atoi(m[2])
+			if r.Min <= r.Max {
+				exp.Ranges = append(exp.Ranges, r)
+				return
+			}
+		}
+	}
+
+	panic(fmt.Sprintf("Invalid exit-code %v, expected a number or a range like 1-125", value))
+}
+
+// Convert the validate property which is either a single command or a list of commands
+func toValidators(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var validators []string
+		for _, c := range v {
+			validators = append(validators, toString(c))
+		}
+		return validators
+	default:
+		panic(fmt.Sprintf("Failed to parse validate with values: %v", value))
+	}
+}
+
+// Convert the run-if and skip-if properties, property is used in error messages
+func toCondition(c *YAMLCondition, property string) *runtime.Condition {
+	if c == nil {
+		return nil
+	}
+
+	return &runtime.Condition{
+		OS:       toStrings(c.OS, property+".os"),
+		Arch:     toStrings(c.Arch, property+".arch"),
+		Binaries: toStrings(c.Binary, property+".binary"),
+		Env:      toStrings(c.Env, property+".env"),
+		Commands: toStrings(c.Command, property+".command"),
+	}
+}
+
+// Convert a string or a list of strings, property is used in error messages
+func toStrings(value interface{}, property string) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var values []string
+		for _, e := range v {
+			if _, ok := e.([]interface{}); ok {
+				panic(fmt.Sprintf("Failed to parse %s, expected a string or a list of strings: %v", property, value))
+			}
+			values = append(values, fmt.Sprintf("%v", e))
+		}
+		return values
+	case map[interface{}]interface{}:
+		panic(fmt.Sprintf("Failed to parse %s, expected a string or a list of strings: %v", property, value))
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
 // Convert variable to string and remove trailing blank lines
 func toString(s interface{}) string {
 	return strings.Trim(fmt.Sprintf("%s", s), "\n")
 }
 
+// Convert the normalize list, entries are either the name of a built-in normalizer
+// or a map with a regex and its replacement
+func toNormalizers(value interface{}) []runtime.Normalizer {
+	values, ok := value.([]interface{})
+	if !ok {
+		panic(fmt.Sprintf("Failed to parse normalize, expected a list: %v", value))
+	}
+
+	var normalizers []runtime.Normalizer
+	for _, v := range values {
+		var n runtime.Normalizer
+		switch v := v.(type) {
+		case string:
+			n.Name = v
+		case map[interface{}]interface{}:
+			for k := range v {
+				if k != "regex" && k != "replace" {
+					panic(fmt.Sprintf("Key %s is not allowed in normalize.", k))
+				}
+			}
+			if v["regex"] == nil {
+				panic("Key regex is required in normalize.")
+			}
+			var replace string
+			if v["replace"] != nil {
+				replace = fmt.Sprintf("%v", v["replace"])
+			}
+			var err error
+			if n, err = runtime.NewRegexNormalizer(toString(v["regex"]), replace); err != nil {
+				panic(err.Error())
+			}
+		default:
+			panic(fmt.Sprintf("Failed to parse normalize with values: %v", v))
+		}
+
+		if err := n.Validate(); err != nil {
+			panic(err.Error())
+		}
+		normalizers = append(normalizers, n)
+	}
+
+	return normalizers
+}
+
 // UnmarshalYAML unmarshals the yaml
 func (y *YAMLSuiteConf) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var params struct {
-		Tests  map[string]YAMLTest     `yaml:"tests"`
-		Config YAMLTestConfigConf      `yaml:"config"`
-		Nodes  map[string]YAMLNodeConf `yaml:"nodes"`
+		Tests     map[string]YAMLTest     `yaml:"tests"`
+		Config    YAMLTestConfigConf      `yaml:"config"`
+		Nodes     map[string]YAMLNodeConf `yaml:"nodes"`
+		Include   interface{}             `yaml:"include"`
+		Templates interface{}             `yaml:"templates"`
+		Tags      []string                `yaml:"tags"`
 	}
 
 	err := unmarshal(&params)
@@ -145,17 +376,49 @@ func (y *YAMLSuiteConf) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 
+	// templates are merged on the raw values, the typed tests can not distinguish unset from empty values
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	templates, _ := raw["templates"].(map[interface{}]interface{})
+	if params.Templates != nil && templates == nil {
+		panic(fmt.Sprintf("Failed to parse templates, expected a map, got %v", params.Templates))
+	}
+	y.addTemplates(templates)
+	y.validateTemplates()
+
+	rawTests, _ := raw["tests"].(map[interface{}]interface{})
+	for k, v := range params.Tests {
+		if v.Extends == nil {
+			continue
+		}
+		test, _ := rawTests[k].(map[interface{}]interface{})
+		params.Tests[k] = y.extendTest(k, test)
+	}
+
 	// map key to title property
 	y.Tests = make(map[string]YAMLTest)
 	for k, v := range params.Tests {
+		// binary assertions require the raw output of all streams
+		raw := v.RawOutput || usesBinaryAssertion(v.Stdout) || usesBinaryAssertion(v.Stderr) || usesBinaryAssertion(v.Output)
+
 		test := YAMLTest{
-			Title:    k,
-			Command:  v.Command,
-			ExitCode: v.ExitCode,
-			Stdout:   y.convertToExpectedOut(v.Stdout),
-			Stderr:   y.convertToExpectedOut(v.Stderr),
-			Config:   v.Config,
-			Skip:     v.Skip,
+			Title:       k,
+			Description: v.Description,
+			Command:     v.Command,
+			ExitCode:    v.ExitCode,
+			Stdout:      y.convertToExpectedOut(v.Stdout, raw),
+			Stderr:      y.convertToExpectedOut(v.Stderr, raw),
+			Output:      y.convertToExpectedOut(v.Output, raw),
+			Config:      v.Config,
+			Skip:        v.Skip,
+			Validate:    v.Validate,
+			RawOutput:   v.RawOutput,
+			Tags:        v.Tags,
+			RunIf:       v.RunIf,
+			SkipIf:      v.SkipIf,
 		}
 
 		// Set key as command, if command property was empty
@@ -163,6 +426,11 @@ func (y *YAMLSuiteConf) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			test.Command = k
 		}
 
+		// Default to exit code 0, if exit-code property was empty
+		if v.ExitCode == nil {
+			test.ExitCode = 0
+		}
+
 		y.Tests[k] = test
 	}
 
@@ -192,15 +460,22 @@ func (y *YAMLSuiteConf) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Retries:    params.Config.Retries,
 		Interval:   params.Config.Interval,
 		Nodes:      params.Config.Nodes,
+		MaxOutput:  params.Config.MaxOutput,
 	}
 
+	y.Include = toIncludes(params.Include)
+	y.Tags = params.Tags
+
 	return nil
 }
 
-// Converts given value to an ExpectedOut. Especially used for Stdout and Stderr.
-func (y *YAMLSuiteConf) convertToExpectedOut(value interface{}) runtime.ExpectedOut {
+// Converts given value to an ExpectedOut. Especially used for Stdout, Stderr and Output.
+// All keys except normalize are parsed by the assertions registered in the matcher package,
+// with raw output the raw parsers of the assertions are used.
+func (y *YAMLSuiteConf) convertToExpectedOut(value interface{}, raw bool) runtime.ExpectedOut {
 	exp := runtime.ExpectedOut{
-		JSON: make(map[string]string),
+		JSON:      make(map[string]string),
+		RawOutput: raw,
 	}
 
 	switch value.(type) {
@@ -210,68 +485,42 @@ func (y *YAMLSuiteConf) convertToExpectedOut(value interface{}) runtime.Expected
 
 	// If there is nested map set the properties will be assigned to the contains
 	case map[interface{}]interface{}:
-		v := value.(map[interface{}]interface{})
-		// Check if keys are parsable
-		// TODO: Could be refactored into a registry maybe which holds all parsers
-		for k := range v {
-			switch k {
-			case
-				"contains",
-				"exactly",
-				"line-count",
-				"lines",
-				"json",
-				"xml",
-				"file",
-				"not-contains":
-			default:
-				panic(fmt.Sprintf("Key %s is not allowed.", k))
+		for k, v := range value.(map[interface{}]interface{}) {
+			key := fmt.Sprint(k)
+			if key == "normalize" {
+				exp.Normalize = toNormalizers(v)
+				continue
 			}
-		}
 
-		// Parse contains key
-		if contains := v["contains"]; contains != nil {
-			values := contains.([]interface{})
-			for _, v := range values {
-				exp.Contains = append(exp.Contains, toString(v))
+			if key == "message" {
+				exp.Message = toString(v)
+				continue
 			}
-		}
 
-		// Parse exactly key
-		if exactly := v["exactly"]; exactly != nil {
-			exp.Exactly = toString(exactly)
-		}
-
-		// Parse file key
-		if file := v["file"]; file != nil {
-			exp.File = toString(file)
-		}
+			if key == "messages" {
+				exp.Messages = toMessages(v)
+				continue
+			}
 
-		// Parse line-count key
-		if lc := v["line-count"]; lc != nil {
-			exp.LineCount = lc.(int)
-		}
+			a, ok := matcher.GetAssertion(key)
+			if !ok {
+				panic(fmt.Sprintf("Key %s is not allowed.", k))
+			}
 
-		// Parse lines
-		if l := v["lines"]; l != nil {
-			exp.Lines = make(map[int]string)
-			for k, v := range l.(map[interface{}]interface{}) {
-				exp.Lines[k.(int)] = toString(v)
+			if v == nil {
+				continue
 			}
-		}
 
-		if notContains := v["not-contains"]; notContains != nil {
-			values := notContains.([]interface{})
-			for _, v := range values {
-				exp.NotContains = append(exp.NotContains, toString(v))
+			parse := a.Parse
+			if raw && a.RawParse != nil {
+				parse = a.RawParse
 			}
-		}
 
-		if json := v["json"]; json != nil {
-			values := json.(map[interface{}]interface{})
-			for k, v := range values {
-				exp.JSON[k.(string)] = v.(string)
+			parsed, err := parse(v)
+			if err != nil {
+				panic(fmt.Sprintf("Failed to parse %s: %s", key, err))
 			}
+			exp.Set(key, parsed)
 		}
 	case nil:
 		break
@@ -282,6 +531,55 @@ func (y *YAMLSuiteConf) convertToExpectedOut(value interface{}) runtime.Expected
 	return exp
 }
 
+// toMessages converts the messages of the assertions, the keys have to be registered assertions
+func toMessages(value interface{}) map[string]string {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		panic(fmt.Sprintf("Failed to parse messages, expected a map of assertions and messages, got %v", value))
+	}
+
+	messages := make(map[string]string)
+	for k, v := range values {
+		key := fmt.Sprint(k)
+		if _, ok := matcher.GetAssertion(key); !ok {
+			panic(fmt.Sprintf("Failed to parse messages, assertion '%s' does not exist", key))
+		}
+		messages[key] = toString(v)
+	}
+	return messages
+}
+
+// usesBinaryAssertion checks if the given stdout, stderr or output value contains a binary assertion,
+// including the branches of any-of, all-of and not
+func usesBinaryAssertion(value interface{}) bool {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+
+	for k, v := range values {
+		key := fmt.Sprint(k)
+		if a, ok := matcher.GetAssertion(key); ok && a.Binary {
+			return true
+		}
+
+		if !matcher.IsOperator(key) {
+			continue
+		}
+
+		branches, ok := v.([]interface{})
+		if !ok {
+			branches = []interface{}{v}
+		}
+		for _, b := range branches {
+			if usesBinaryAssertion(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // MarshalYAML adds custom logic to the struct to yaml conversion
 func (y YAMLSuiteConf) MarshalYAML() (interface{}, error) {
 	// Detect which values of the stdout/stderr assertions should be filled.
@@ -298,6 +596,10 @@ func (y YAMLSuiteConf) MarshalYAML() (interface{}, error) {
 			t.Stderr = t.Stderr.(runtime.ExpectedOut)
 		}
 
+		if out, ok := t.Output.(runtime.ExpectedOut); ok {
+			t.Output = convertExpectedOut(out)
+		}
+
 		y.Tests[k] = t
 	}
 
@@ -328,9 +630,24 @@ func convertExpectedOut(out runtime.ExpectedOut) interface{} {
 
 func propertiesAreEmpty(out runtime.ExpectedOut) bool {
 	return out.Lines == nil &&
+		out.ContainsCount == nil &&
+		out.ContainsInOrder == nil &&
+		out.Transcript == nil &&
+		out.AnyOf == nil &&
+		out.AllOf == nil &&
+		out.Not == nil &&
+		out.Message == "" &&
+		out.Messages == nil &&
 		out.Exactly == "" &&
 		out.LineCount == 0 &&
-		out.NotContains == nil
+		out.NotContains == nil &&
+		out.Table == nil &&
+		out.ByteSize == nil &&
+		out.SHA256 == "" &&
+		out.MD5 == "" &&
+		out.BinaryFile == "" &&
+		out.Normalize == nil &&
+		len(out.Custom) == 0
 }
 
 func isContainsASingleNonEmptyString(out runtime.ExpectedOut) bool {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/commander-cli/commander/v2/pkg/matcher"
 	"github.com/commander-cli/commander/v2/pkg/runtime"
 )
 
@@ -44,6 +45,54 @@ tests:
 	assert.Equal(t, "echo hello", tests[0].Title)
 }
 
+func TestYAMLConfig_UnmarshalYAML_ShouldMergeSuiteTags(t *testing.T) {
+	yaml := []byte(`
+tags: [cli, smoke]
+tests:
+    echo hello:
+        tags: [smoke, fast]
+    echo bye:
+        exit-code: 0
+`)
+	s := ParseYAML(yaml, "")
+
+	hello, _ := s.GetTestByTitle("echo hello")
+	assert.Equal(t, []string{"cli", "smoke", "fast"}, hello.Tags)
+	bye, _ := s.GetTestByTitle("echo bye")
+	assert.Equal(t, []string{"cli", "smoke"}, bye.Tags)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseConditions(t *testing.T) {
+	yaml := []byte(`
+templates:
+    linux:
+        run-if:
+            os: linux
+tests:
+    apt --version:
+        extends: linux
+        skip-if:
+            binary: [docker, podman]
+            env: CI=true
+            command: test -f /.dockerenv
+    echo hello:
+        exit-code: 0
+`)
+	s := ParseYAML(yaml, "")
+
+	apt, _ := s.GetTestByTitle("apt --version")
+	assert.Equal(t, &runtime.Condition{OS: []string{"linux"}}, apt.RunIf)
+	assert.Equal(t, &runtime.Condition{
+		Binaries: []string{"docker", "podman"},
+		Env:      []string{"CI=true"},
+		Commands: []string{"test -f /.dockerenv"},
+	}, apt.SkipIf)
+
+	hello, _ := s.GetTestByTitle("echo hello")
+	assert.Nil(t, hello.RunIf)
+	assert.Nil(t, hello.SkipIf)
+}
+
 func TestYAMLConfig_UnmarshalYAML_ShouldParseLineCount(t *testing.T) {
 	yaml := []byte(`
 tests:
@@ -64,15 +113,15 @@ tests:
         exit-code: 0
         stdout:
             lines:
-                0: line1
-                1: line2
-                3: line4
+                1: line1
+                2: line2
+                4: line4
 `)
 	tests := ParseYAML(yaml, "").GetTests()
 
-	assert.Equal(t, "line1", tests[0].Expected.Stdout.Lines[0])
-	assert.Equal(t, "line2", tests[0].Expected.Stdout.Lines[1])
-	assert.Equal(t, "line4", tests[0].Expected.Stdout.Lines[3])
+	assert.Equal(t, "line1", tests[0].Expected.Stdout.Lines[1])
+	assert.Equal(t, "line2", tests[0].Expected.Stdout.Lines[2])
+	assert.Equal(t, "line4", tests[0].Expected.Stdout.Lines[4])
 }
 
 func TestYAMLConfig_UnmarshalYAML_ShouldDisable(t *testing.T) {
@@ -138,7 +187,7 @@ func Test_YAMLConfig_convertToExpectedOut(t *testing.T) {
 	in := map[interface{}]interface{}{"exactly": "exactly stderr"}
 
 	y := YAMLSuiteConf{}
-	got := y.convertToExpectedOut(in)
+	got := y.convertToExpectedOut(in, false)
 
 	assert.IsType(t, runtime.ExpectedOut{}, got)
 	assert.Equal(t, "exactly stderr", got.Exactly)
@@ -418,3 +467,418 @@ tests:
 	assert.Contains(t, got.GetTests()[0].Nodes, "docker-host")
 	assert.Contains(t, got.GetTests()[0].Nodes, "ssh-host1")
 }
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseTable(t *testing.T) {
+	yaml := []byte(`
+tests:
+    kubectl get pods:
+        stdout:
+            table:
+                format: whitespace
+                row-count: 2
+                cells:
+                    - row: 1
+                      column: NAME
+                      value: web-1
+                rows:
+                    - NAME: db-1
+                      STATUS: Pending
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	table := tests[0].Expected.Stdout.Table
+	assert.Equal(t, "whitespace", table.Format)
+	assert.Equal(t, 2, *table.RowCount)
+	assert.Equal(t, "web-1", table.Cells[0].Value)
+	assert.Equal(t, map[string]string{"NAME": "db-1", "STATUS": "Pending"}, table.Rows[0])
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnUnknownTableKey(t *testing.T) {
+	defer func() {
+		r := recover()
+		assert.Contains(t, r, "Failed to parse table")
+	}()
+
+	yaml := []byte(`
+tests:
+    kubectl get pods:
+        stdout:
+            table:
+                row-counts: 2
+`)
+	_ = ParseYAML(yaml, "")
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseExitCodes(t *testing.T) {
+	yaml := []byte(`
+tests:
+    exact:
+        exit-code: 2
+    list:
+        exit-code: [1, 2, "10-20"]
+    range:
+        exit-code: 1-125
+    not:
+        exit-code:
+            not: 0
+    any:
+        exit-code: any
+    default:
+        stdout: hello
+`)
+	s := ParseYAML(yaml, "")
+
+	test, _ := s.GetTestByTitle("exact")
+	assert.Equal(t, 2, test.Expected.ExitCode)
+	assert.Nil(t, test.Expected.ExitCodes)
+
+	test, _ = s.GetTestByTitle("list")
+	assert.Equal(t, []int{1, 2}, test.Expected.ExitCodes.Codes)
+	assert.Equal(t, []matcher.ExitCodeRange{{Min: 10, Max: 20}}, test.Expected.ExitCodes.Ranges)
+
+	test, _ = s.GetTestByTitle("range")
+	assert.Equal(t, []matcher.ExitCodeRange{{Min: 1, Max: 125}}, test.Expected.ExitCodes.Ranges)
+
+	test, _ = s.GetTestByTitle("not")
+	assert.Equal(t, []int{0}, test.Expected.ExitCodes.Not)
+
+	test, _ = s.GetTestByTitle("any")
+	assert.True(t, test.Expected.ExitCodes.Any)
+
+	test, _ = s.GetTestByTitle("default")
+	assert.Equal(t, 0, test.Expected.ExitCode)
+	assert.Nil(t, test.Expected.ExitCodes)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnInvalidExitCode(t *testing.T) {
+	defer func() {
+		r := recover()
+		assert.Contains(t, r, "Invalid exit-code 5-1")
+	}()
+
+	yaml := []byte(`
+tests:
+    echo hello:
+        exit-code: 5-1
+`)
+	_ = ParseYAML(yaml, "")
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnExitCodeRangeWithTrailingText(t *testing.T) {
+	for _, code := range []string{"10-50foo", "1-2-3", "-1-5", " 1-5"} {
+		assert.PanicsWithValue(t, "Invalid exit-code "+code+", expected a number or a range like 1-125", func() {
+			toExitCodeExpectation([]interface{}{code})
+		}, code)
+	}
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnEmptyExitCodeList(t *testing.T) {
+	assert.PanicsWithValue(t, "Invalid exit-code [], expected at least one exit code or range, use any to accept all exit codes", func() {
+		toExitCodeExpectation([]interface{}{})
+	})
+	assert.PanicsWithValue(t, "Invalid exit-code [] given in not, expected at least one exit code", func() {
+		toExitCodeExpectation(map[interface{}]interface{}{"not": []interface{}{}})
+	})
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseNormalize(t *testing.T) {
+	yaml := []byte(`
+tests:
+    echo hello:
+        stdout:
+            normalize:
+                - strip-ansi
+                - regex: '\d+'
+                  replace: <N>
+            exactly: hello
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	digits, _ := runtime.NewRegexNormalizer(`\d+`, "<N>")
+	assert.Equal(t, []runtime.Normalizer{
+		{Name: "strip-ansi"},
+		digits,
+	}, tests[0].Expected.Stdout.Normalize)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnUnknownNormalizer(t *testing.T) {
+	defer func() {
+		r := recover()
+		assert.Equal(t, "normalizer uppercase does not exist", r)
+	}()
+
+	yaml := []byte(`
+tests:
+    echo hello:
+        stdout:
+            normalize:
+                - uppercase
+`)
+	_ = ParseYAML(yaml, "")
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseValidate(t *testing.T) {
+	yaml := []byte(`
+tests:
+    single:
+        command: echo hello
+        validate: ./check.sh
+    multiple:
+        command: echo hello
+        validate:
+            - ./check.sh
+            - grep -q hello
+`)
+	s := ParseYAML(yaml, "")
+
+	test, _ := s.GetTestByTitle("single")
+	assert.Equal(t, []string{"./check.sh"}, test.Expected.Validators)
+
+	test, _ = s.GetTestByTitle("multiple")
+	assert.Equal(t, []string{"./check.sh", "grep -q hello"}, test.Expected.Validators)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseXML(t *testing.T) {
+	yaml := []byte(`
+tests:
+    cat book.xml:
+        stdout:
+            xml:
+                /book//author: J. R. R. Tolkien
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.Equal(t, map[string]string{"/book//author": "J. R. R. Tolkien"}, tests[0].Expected.Stdout.XML)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseRegisteredAssertion(t *testing.T) {
+	t.Cleanup(matcher.RegisterAssertion(matcher.Assertion{
+		Key:     "suite-test-starts-with",
+		Parse:   matcher.ParseString,
+		Matcher: matcher.TextMatcher{},
+	}))
+
+	yaml := []byte(`
+tests:
+    echo hello:
+        stdout:
+            suite-test-starts-with: hello
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.Equal(t, map[string]interface{}{"suite-test-starts-with": "hello"}, tests[0].Expected.Stdout.Custom)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnUnknownAssertion(t *testing.T) {
+	defer func() {
+		r := recover()
+		assert.Equal(t, "Key starts-with is not allowed.", r)
+	}()
+
+	yaml := []byte(`
+tests:
+    echo hello:
+        stdout:
+            starts-with: hello
+`)
+	_ = ParseYAML(yaml, "")
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseOutput(t *testing.T) {
+	yaml := []byte(`
+tests:
+    echo hello:
+        output:
+            exactly: hello
+            line-count: 1
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.Equal(t, "hello", tests[0].Expected.Output.Exactly)
+	assert.Equal(t, 1, tests[0].Expected.Output.LineCount)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseRawOutput(t *testing.T) {
+	yaml := []byte(`
+tests:
+    printf "hello\n":
+        raw-output: true
+        stdout:
+            exactly: "hello\n"
+    echo hello:
+        stdout:
+            exactly: "hello\n"
+`)
+	s := ParseYAML(yaml, "")
+
+	test, _ := s.GetTestByTitle(`printf "hello\n"`)
+	assert.True(t, test.Command.RawOutput)
+	assert.True(t, test.Expected.Stdout.RawOutput)
+	assert.Equal(t, "hello\n", test.Expected.Stdout.Exactly)
+
+	test, _ = s.GetTestByTitle("echo hello")
+	assert.False(t, test.Command.RawOutput)
+	assert.Equal(t, "hello", test.Expected.Stdout.Exactly)
+}
+
+func TestYAMLConfig_UnmarshalYAML_BinaryAssertionsShouldUseRawOutput(t *testing.T) {
+	yaml := []byte(`
+tests:
+    cat image.png:
+        stdout:
+            byte-size: 0
+            sha256: 2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824
+            binary-file: ./image.png
+        stderr:
+            exactly: "warning\n"
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.True(t, tests[0].Command.RawOutput)
+	assert.Equal(t, 0, *tests[0].Expected.Stdout.ByteSize)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", tests[0].Expected.Stdout.SHA256)
+	assert.Equal(t, "./image.png", tests[0].Expected.Stdout.BinaryFile)
+	assert.Equal(t, "warning\n", tests[0].Expected.Stderr.Exactly)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseMaxOutput(t *testing.T) {
+	yaml := []byte(`
+config:
+    max-output: 10MB
+tests:
+    echo hello:
+        exit-code: 0
+    seq 1 100000:
+        config:
+            max-output: 1KB
+        exit-code: 0
+`)
+	s := NewSuite(yaml, []byte{}, "")
+
+	assert.Equal(t, "10MB", s.GetGlobalConfig().MaxOutput)
+
+	test, _ := s.GetTestByTitle("echo hello")
+	assert.Equal(t, "10MB", test.Command.MaxOutput)
+
+	test, _ = s.GetTestByTitle("seq 1 100000")
+	assert.Equal(t, "1KB", test.Command.MaxOutput)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseCountedAndOrderedContains(t *testing.T) {
+	yaml := []byte(`
+tests:
+    ./build.sh:
+        stdout:
+            contains:
+                - done
+                - text: WARN
+                  count: 1
+                - text: INFO
+                  min: 1
+                  max: 3
+            contains-in-order:
+                - Step 1
+                - Step 2
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	one, three := 1, 3
+	assert.Equal(t, []string{"done"}, tests[0].Expected.Stdout.Contains)
+	assert.Equal(t, []matcher.ContainsCount{
+		{Text: "WARN", Count: &one},
+		{Text: "INFO", Min: &one, Max: &three},
+	}, tests[0].Expected.Stdout.ContainsCount)
+	assert.Equal(t, []string{"Step 1", "Step 2"}, tests[0].Expected.Stdout.ContainsInOrder)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnInvalidContainsCount(t *testing.T) {
+	yaml := []byte(`
+tests:
+    ./build.sh:
+        stdout:
+            contains:
+                - text: WARN
+                  count: 1
+                  max: 2
+`)
+	assert.Panics(t, func() { ParseYAML(yaml, "") })
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseOperators(t *testing.T) {
+	yaml := []byte(`
+tests:
+    uname:
+        stdout:
+            any-of:
+                - exactly: Linux
+                - all-of:
+                    - contains: Darwin
+                    - not:
+                        contains: error
+            not:
+                line-count: 0
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	stdout := tests[0].Expected.Stdout
+	assert.Len(t, stdout.AnyOf, 2)
+	assert.Equal(t, "exactly", stdout.AnyOf[0][0].Key)
+	assert.Equal(t, "all-of", stdout.AnyOf[1][0].Key)
+	assert.Equal(t, "line-count", stdout.Not[0].Key)
+}
+
+func TestYAMLConfig_UnmarshalYAML_BinaryAssertionInOperatorShouldUseRawOutput(t *testing.T) {
+	yaml := []byte(`
+tests:
+    cat image.png:
+        stdout:
+            any-of:
+                - md5: 5d41402abc4b2a76b9719d911017c592
+                - exactly: "hello\n"
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.True(t, tests[0].Command.RawOutput)
+	assert.Equal(t, "hello\n", tests[0].Expected.Stdout.AnyOf[1][0].Expected)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnInvalidOperator(t *testing.T) {
+	yaml := []byte(`
+tests:
+    uname:
+        stdout:
+            any-of:
+                - unknown: Linux
+`)
+	assert.PanicsWithValue(t, "Failed to parse any-of: branch 1: assertion 'unknown' does not exist", func() { ParseYAML(yaml, "") })
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldParseDescriptionAndMessages(t *testing.T) {
+	yaml := []byte(`
+tests:
+    ./server --version:
+        description: guards the version banner of the release
+        stdout:
+            contains: v2
+            line-count: 1
+            message: version banner changed
+            messages:
+                line-count: banner has additional lines
+`)
+	tests := ParseYAML(yaml, "").GetTests()
+
+	assert.Equal(t, "guards the version banner of the release", tests[0].Description)
+	assert.Equal(t, "version banner changed", tests[0].Expected.Stdout.Message)
+	assert.Equal(t, map[string]string{"line-count": "banner has additional lines"}, tests[0].Expected.Stdout.Messages)
+}
+
+func TestYAMLConfig_UnmarshalYAML_ShouldPanicOnMessageOfUnknownAssertion(t *testing.T) {
+	yaml := []byte(`
+tests:
+    echo hello:
+        stdout:
+            messages:
+                unknown: message
+`)
+	assert.PanicsWithValue(t, "Failed to parse messages, assertion 'unknown' does not exist", func() { ParseYAML(yaml, "") })
+}